@@ -0,0 +1,357 @@
+package ines
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// BPS patches describe the target as a list of actions which copy data from the source, from the patch,
+// or from the target written so far:
+//
+//	"BPS1"
+//	source size, target size, metadata size (varints), metadata
+//	action (varint: length-1 << 2 | command), followed by the data or the relative offset the command needs
+//	...
+//	source CRC32, target CRC32, patch CRC32 (4 bytes each, little-endian)
+//
+// https://github.com/blakesmith/rombp/blob/master/docs/bps_spec.md
+const (
+	bpsMagic      = "BPS1"
+	bpsFooterSize = 12
+	bpsMinMatch   = 4       // shorter matches cost more than the data itself
+	bpsMaxChain   = 32      // candidates tried per position, to bound the time on repetitive data
+	bpsMaxSize    = 1 << 30 // far above any NES file, to refuse building what a corrupted size asks for
+)
+
+// The commands of the BPS actions.
+const (
+	bpsSourceRead = iota // copy from the source, at the same offset as the target
+	bpsTargetRead        // copy from the patch
+	bpsSourceCopy        // copy from the source, at a relative offset
+	bpsTargetCopy        // copy from the target written so far, at a relative offset
+)
+
+// ApplyBPS applies the BPS patch to source and returns the target.
+// It returns ErrSourceMismatch if source is not the file the patch was made for,
+// and ErrTargetMismatch if the result is not the one the patch was made to produce.
+// nolint: gomnd, cyclop, funlen
+func ApplyBPS(source []byte, patch []byte) ([]byte, error) {
+	if !bytes.HasPrefix(patch, []byte(bpsMagic)) || len(patch) < len(bpsMagic)+bpsFooterSize {
+		return nil, fmt.Errorf("%w: no BPS header", ErrBadPatch)
+	}
+
+	footer := patch[len(patch)-bpsFooterSize:]
+	sourceCRC, targetCRC := binary.LittleEndian.Uint32(footer), binary.LittleEndian.Uint32(footer[4:])
+
+	if crc, want := crc32.ChecksumIEEE(patch[:len(patch)-4]), binary.LittleEndian.Uint32(footer[8:]); crc != want {
+		return nil, fmt.Errorf("%w: BPS patch checksum is %08X, want %08X", ErrBadPatch, crc, want)
+	}
+
+	if crc := crc32.ChecksumIEEE(source); crc != sourceCRC {
+		return nil, fmt.Errorf("%w: CRC32 is %08X, want %08X", ErrSourceMismatch, crc, sourceCRC)
+	}
+
+	r := &varintReader{b: patch[len(bpsMagic) : len(patch)-bpsFooterSize]}
+	sourceSize, targetSize, metadataSize := r.read(), r.read(), r.read()
+	switch {
+	case sourceSize > bpsMaxSize:
+		return nil, fmt.Errorf("%w: BPS source of %v bytes", ErrPatchTooLarge, sourceSize)
+	case targetSize > bpsMaxSize:
+		return nil, fmt.Errorf("%w: BPS target of %v bytes", ErrPatchTooLarge, targetSize)
+	}
+
+	r.skip(metadataSize)
+
+	if r.err != nil || sourceSize != uint64(len(source)) {
+		return nil, fmt.Errorf("%w: bad BPS sizes", ErrBadPatch)
+	}
+
+	var target []byte
+
+	var sourceOffset, targetOffset int
+
+	for len(r.b) != 0 && r.err == nil {
+		action := r.read()
+		command, length := action&3, int(action>>2)+1
+
+		if uint64(len(target)+length) > targetSize {
+			return nil, fmt.Errorf("%w: BPS action writes past the target", ErrBadPatch)
+		}
+
+		switch command {
+		case bpsSourceRead:
+			if len(target)+length > len(source) {
+				return nil, fmt.Errorf("%w: BPS source read past the source", ErrBadPatch)
+			}
+
+			target = append(target, source[len(target):len(target)+length]...)
+		case bpsTargetRead:
+			if length > len(r.b) {
+				return nil, fmt.Errorf("%w: truncated BPS target read", ErrBadPatch)
+			}
+
+			target = append(target, r.b[:length]...)
+			r.b = r.b[length:]
+		case bpsSourceCopy:
+			sourceOffset += r.readSigned()
+			if sourceOffset < 0 || sourceOffset+length > len(source) {
+				return nil, fmt.Errorf("%w: BPS source copy past the source", ErrBadPatch)
+			}
+
+			target = append(target, source[sourceOffset:sourceOffset+length]...)
+			sourceOffset += length
+		case bpsTargetCopy:
+			targetOffset += r.readSigned()
+			if targetOffset < 0 || targetOffset >= len(target) {
+				return nil, fmt.Errorf("%w: BPS target copy past the target", ErrBadPatch)
+			}
+
+			// The copy may overlap the bytes it writes, so it goes one byte at a time
+			for i := 0; i < length; i++ {
+				target = append(target, target[targetOffset])
+				targetOffset++
+			}
+		}
+	}
+
+	if r.err != nil || uint64(len(target)) != targetSize {
+		return nil, fmt.Errorf("%w: BPS actions don't produce the target", ErrBadPatch)
+	}
+
+	if crc := crc32.ChecksumIEEE(target); crc != targetCRC {
+		return nil, fmt.Errorf("%w: CRC32 is %08X, want %08X", ErrTargetMismatch, crc, targetCRC)
+	}
+
+	return target, nil
+}
+
+// CreateBPS returns the BPS patch which turns source into target.
+// The delta encoder looks for every run of the target in the source and in the target written so far,
+// so that relocated and repeated data is copied rather than stored in the patch.
+// nolint: gomnd
+func CreateBPS(source []byte, target []byte) []byte {
+	w := &bpsWriter{patch: []byte(bpsMagic)}
+	w.varint(uint64(len(source)))
+	w.varint(uint64(len(target)))
+	w.varint(0) // no metadata
+
+	sourceIndex := newMatchIndex(source)
+	targetIndex := newMatchIndex(nil)
+
+	var literal int // start of the target bytes waiting to be written by a target read
+
+	for pos := 0; pos < len(target); {
+		// Source read costs the least, so it wins ties
+		command, offset, length := bpsSourceRead, pos, matchLength(source, pos, target, pos)
+
+		if o, l := sourceIndex.longest(source, target, pos); l > length+1 {
+			command, offset, length = bpsSourceCopy, o, l
+		}
+
+		// The target copy may overlap the bytes it writes, which is how runs get encoded
+		if o, l := targetIndex.longest(target, target, pos); l > length+1 {
+			command, offset, length = bpsTargetCopy, o, l
+		}
+
+		if length < bpsMinMatch {
+			targetIndex.add(target, pos)
+			pos++
+
+			continue
+		}
+
+		w.targetRead(target[literal:pos])
+
+		switch command {
+		case bpsSourceRead:
+			w.action(bpsSourceRead, length)
+		case bpsSourceCopy:
+			w.copy(bpsSourceCopy, &w.sourceOffset, offset, length)
+		case bpsTargetCopy:
+			w.copy(bpsTargetCopy, &w.targetOffset, offset, length)
+		}
+
+		for end := pos + length; pos < end; pos++ {
+			targetIndex.add(target, pos)
+		}
+
+		literal = pos
+	}
+
+	w.targetRead(target[literal:])
+
+	return appendFooter(w.patch, source, target)
+}
+
+// bpsWriter writes BPS actions, keeping track of the relative offsets.
+type bpsWriter struct {
+	patch        []byte
+	sourceOffset int
+	targetOffset int
+}
+
+func (w *bpsWriter) varint(n uint64) {
+	w.patch = appendVarint(w.patch, n)
+}
+
+func (w *bpsWriter) action(command int, length int) {
+	w.varint(uint64(length-1)<<2 | uint64(command))
+}
+
+func (w *bpsWriter) targetRead(data []byte) {
+	if len(data) != 0 {
+		w.action(bpsTargetRead, len(data))
+		w.patch = append(w.patch, data...)
+	}
+}
+
+// copy writes a copy action from offset, relative to the previous one.
+func (w *bpsWriter) copy(command int, relative *int, offset int, length int) {
+	w.action(command, length)
+
+	delta := offset - *relative
+	if delta < 0 {
+		w.varint(uint64(-delta)<<1 | 1)
+	} else {
+		w.varint(uint64(delta) << 1)
+	}
+
+	*relative = offset + length
+}
+
+// appendVarint appends n in the variable-length encoding of BPS and UPS, where each byte holds 7 bits
+// and the last one has its top bit set.
+// nolint: gomnd
+func appendVarint(b []byte, n uint64) []byte {
+	for {
+		x := byte(n & 0x7F)
+		n >>= 7
+
+		if n == 0 {
+			return append(b, 0x80|x)
+		}
+
+		b = append(b, x)
+		n--
+	}
+}
+
+// appendFooter appends the CRC32 of the input, of the output and of the patch, as BPS and UPS end with.
+func appendFooter(patch []byte, input []byte, output []byte) []byte {
+	patch = appendUint32(patch, crc32.ChecksumIEEE(input))
+	patch = appendUint32(patch, crc32.ChecksumIEEE(output))
+
+	return appendUint32(patch, crc32.ChecksumIEEE(patch))
+}
+
+// nolint: gomnd
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// varintReader reads the numbers of a BPS or UPS patch.
+// Errors stick, so that they can be checked once after several reads.
+type varintReader struct {
+	b   []byte
+	err error
+}
+
+// nolint: gomnd
+func (r *varintReader) read() uint64 {
+	var n, shift uint64 = 0, 1
+
+	for r.err == nil {
+		if len(r.b) == 0 || shift > 1<<56 {
+			r.err = fmt.Errorf("%w: bad variable-length number", ErrBadPatch)
+
+			break
+		}
+
+		x := r.b[0]
+		r.b = r.b[1:]
+		n += uint64(x&0x7F) * shift
+
+		if x&0x80 != 0 {
+			break
+		}
+
+		shift <<= 7
+		n += shift
+	}
+
+	return n
+}
+
+// readSigned reads a relative offset, whose lowest bit is the sign.
+func (r *varintReader) readSigned() int {
+	n := r.read()
+	if n&1 != 0 {
+		return -int(n >> 1)
+	}
+
+	return int(n >> 1)
+}
+
+func (r *varintReader) skip(n uint64) {
+	if n > uint64(len(r.b)) {
+		r.err = fmt.Errorf("%w: truncated patch", ErrBadPatch)
+
+		return
+	}
+
+	r.b = r.b[n:]
+}
+
+// matchIndex finds earlier occurrences of the bytes at a position, by the 4 bytes they start with.
+type matchIndex map[uint32][]int
+
+func newMatchIndex(b []byte) matchIndex {
+	index := make(matchIndex)
+	for i := range b {
+		index.add(b, i)
+	}
+
+	return index
+}
+
+func (m matchIndex) add(b []byte, i int) {
+	if i+bpsMinMatch <= len(b) {
+		key := binary.LittleEndian.Uint32(b[i:])
+		m[key] = append(m[key], i)
+	}
+}
+
+// longest returns the offset in b and the length of the longest match of the bytes of target at pos,
+// among the positions of b which were added to the index.
+func (m matchIndex) longest(b []byte, target []byte, pos int) (int, int) {
+	if pos+bpsMinMatch > len(target) {
+		return 0, 0
+	}
+
+	candidates := m[binary.LittleEndian.Uint32(target[pos:])]
+	if len(candidates) > bpsMaxChain {
+		candidates = candidates[len(candidates)-bpsMaxChain:]
+	}
+
+	var offset, length int
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if l := matchLength(b, candidates[i], target, pos); l > length {
+			offset, length = candidates[i], l
+		}
+	}
+
+	return offset, length
+}
+
+// matchLength returns how many bytes of a from i match the bytes of b from j.
+func matchLength(a []byte, i int, b []byte, j int) int {
+	n := 0
+	for i+n < len(a) && j+n < len(b) && a[i+n] == b[j+n] {
+		n++
+	}
+
+	return n
+}
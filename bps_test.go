@@ -0,0 +1,184 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCreateBPS(t *testing.T) {
+	t.Parallel()
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The PRG-ROM banks swapped around, which a good delta encoder copies rather than stores
+	relocated := append(append(append([]byte{}, demo[:16]...), demo[16+16384:16+32768]...), demo[16:16+16384]...)
+	relocated = append(relocated, demo[16+32768:]...)
+
+	tests := []struct {
+		name    string
+		source  []byte
+		target  []byte
+		maxSize int
+	}{
+		{
+			name:    "same file",
+			source:  demo,
+			target:  demo,
+			maxSize: 32,
+		},
+		{
+			name:    "few changes",
+			source:  demo,
+			target:  modify(demo, 0x100, 0xEA, 0xEA, 0xEA),
+			maxSize: 48,
+		},
+		{
+			name:    "relocated banks",
+			source:  demo,
+			target:  relocated,
+			maxSize: 64,
+		},
+		{
+			name:    "grown with a run",
+			source:  demo,
+			target:  append(append([]byte{}, demo...), bytes.Repeat([]byte{0xFF}, 16384)...),
+			maxSize: 64,
+		},
+		{
+			name:    "from nothing",
+			source:  nil,
+			target:  []byte("NES\x1a0123456789abcdef"),
+			maxSize: 64,
+		},
+		{
+			name:    "truncated",
+			source:  demo,
+			target:  demo[:100],
+			maxSize: 32,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			patch := CreateBPS(tt2.source, tt2.target)
+			if len(patch) > tt2.maxSize {
+				t.Errorf("CreateBPS() size = %v, want at most %v", len(patch), tt2.maxSize)
+			}
+
+			got, err := ApplyBPS(tt2.source, patch)
+			if err != nil {
+				t.Fatalf("ApplyBPS() error = %v", err)
+			}
+
+			if !bytes.Equal(got, tt2.target) {
+				t.Errorf("ApplyBPS() size = %v, want %v", len(got), len(tt2.target))
+			}
+		})
+	}
+}
+
+func TestApplyBPS_errors(t *testing.T) {
+	t.Parallel()
+
+	source, target := []byte("original file"), []byte("modified file")
+	patch := CreateBPS(source, target)
+
+	corrupted := append([]byte{}, patch...)
+	corrupted[len(bpsMagic)+3]++
+
+	// A target larger than any NES file, made of a single target copy so that the patch is small
+	oversized := appendVarint(appendVarint([]byte(bpsMagic), uint64(len(source))), bpsMaxSize+1)
+	oversized = appendVarint(appendVarint(oversized, 0), 0<<2|bpsTargetRead)
+	oversized = appendVarint(append(oversized, 'x'), (bpsMaxSize-1)<<2|bpsTargetCopy)
+	oversized = appendFooter(appendVarint(oversized, 0), source, target)
+
+	oversizedSource := appendVarint(appendVarint([]byte(bpsMagic), bpsMaxSize+1), uint64(len(target)))
+	oversizedSource = appendFooter(appendVarint(oversizedSource, 0), source, target)
+
+	tests := []struct {
+		name    string
+		source  []byte
+		patch   []byte
+		wantErr error
+	}{
+		{
+			name:    "wrong source",
+			source:  []byte("another file!"),
+			patch:   patch,
+			wantErr: ErrSourceMismatch,
+		},
+		{
+			name:    "corrupted patch",
+			source:  source,
+			patch:   corrupted,
+			wantErr: ErrBadPatch,
+		},
+		{
+			name:    "oversized target",
+			source:  source,
+			patch:   oversized,
+			wantErr: ErrPatchTooLarge,
+		},
+		{
+			name:    "oversized source",
+			source:  source,
+			patch:   oversizedSource,
+			wantErr: ErrPatchTooLarge,
+		},
+		{
+			name:    "ips patch",
+			source:  source,
+			patch:   []byte("PATCHEOF"),
+			wantErr: ErrBadPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ApplyBPS(tt2.source, tt2.patch); !errors.Is(err, tt2.wantErr) {
+				t.Errorf("ApplyBPS() error = %v, want %v", err, tt2.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyPatchHeaderless(t *testing.T) {
+	t.Parallel()
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rom, err := Decode(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modified := modify(demo, 16, 0x4C)
+	patch := CreateBPS(demo[16:], modified[16:])
+
+	patched, _, err := ApplyPatchHeaderless(rom, patch)
+	if err != nil {
+		t.Fatalf("ApplyPatchHeaderless() error = %v", err)
+	}
+
+	if patched.ProgramRom[0] != 0x4C || patched.Header != rom.Header {
+		t.Errorf("ApplyPatchHeaderless() prg-rom[0] = %#x, header = % x", patched.ProgramRom[0], patched.Header)
+	}
+
+	// The same patch doesn't apply to the whole file
+	if _, _, err := ApplyPatch(rom, patch); !errors.Is(err, ErrSourceMismatch) {
+		t.Errorf("ApplyPatch() error = %v, want %v", err, ErrSourceMismatch)
+	}
+}
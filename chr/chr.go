@@ -0,0 +1,132 @@
+// Package chr decodes the 2bpp planar tiles of the NES pattern tables, as found in CHR-ROM,
+// into images.
+//
+// Each 8x8 tile takes 16 bytes: 8 bytes for the low bit plane, then 8 bytes for the high bit plane,
+// one byte per row with the leftmost pixel in bit 7.
+// https://wiki.nesdev.org/w/index.php/PPU_pattern_tables
+package chr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+const (
+	TileSize         = 16   // bytes per 8x8 tile
+	TileWidth        = 8    // pixels
+	TilesPerRow      = 16   // tiles per row of the sheet, so that a pattern table is 128x128 pixels
+	PatternTableSize = 4096 // bytes per pattern table, 256 tiles
+)
+
+var (
+	// ErrBadSize is returned when the data is not made of whole tiles.
+	ErrBadSize = errors.New("CHR data is not a multiple of 16 bytes")
+
+	// ErrBadPalette is returned when the palette doesn't have 4 colors.
+	ErrBadPalette = errors.New("palette must have 4 colors")
+)
+
+// Grayscale is the default palette, from the background color 0 to the brightest color 3.
+var Grayscale = color.Palette{ // nolint: gochecknoglobals
+	color.Gray{Y: 0x00},
+	color.Gray{Y: 0x55},
+	color.Gray{Y: 0xAA},
+	color.Gray{Y: 0xFF},
+}
+
+// Options tells how the tiles are laid out in the sheet.
+type Options struct {
+	// Palette gives the colors of the 4 pixel values. Grayscale if nil.
+	Palette color.Palette
+
+	// Tall pairs the tiles as 8x16 sprites do: each even tile is drawn with the next one below it.
+	Tall bool
+}
+
+func (o Options) palette() color.Palette {
+	if o.Palette == nil {
+		return Grayscale
+	}
+
+	return o.Palette
+}
+
+// position returns the top-left pixel of tile i in a sheet of the given number of tiles per row.
+func (o Options) position(i int, perRow int) image.Point {
+	if !o.Tall {
+		return image.Pt(i%perRow*TileWidth, i/perRow*TileWidth)
+	}
+
+	pair := i / 2 // nolint: gomnd
+
+	return image.Pt(pair%perRow*TileWidth, (pair/perRow*2+i%2)*TileWidth)
+}
+
+// bounds returns the size of the sheet for n tiles, 16 tiles wide.
+func (o Options) bounds(n int) image.Rectangle {
+	rows := (n + TilesPerRow - 1) / TilesPerRow
+	if o.Tall {
+		pairs := (n + 1) / 2                               // nolint: gomnd
+		rows = (pairs + TilesPerRow - 1) / TilesPerRow * 2 // nolint: gomnd
+	}
+
+	return image.Rect(0, 0, TilesPerRow*TileWidth, rows*TileWidth)
+}
+
+// DecodeTile returns the pixel values, 0 to 3, of the 16-byte tile, by row.
+func DecodeTile(tile []byte) [TileWidth][TileWidth]uint8 {
+	var pixels [TileWidth][TileWidth]uint8
+
+	for y := 0; y < TileWidth; y++ {
+		low, high := tile[y], tile[y+TileWidth]
+
+		for x := 0; x < TileWidth; x++ {
+			bit := uint(TileWidth - 1 - x)
+			pixels[y][x] = (low>>bit)&1 | (high>>bit&1)<<1
+		}
+	}
+
+	return pixels
+}
+
+// Decode draws the tiles of data in a sheet, 16 tiles per row, so that each 4 KiB pattern table
+// is a 128x128 pixels block and the pattern tables follow each other downwards.
+func Decode(data []byte, opts Options) (*image.Paletted, error) {
+	if len(data)%TileSize != 0 {
+		return nil, fmt.Errorf("%w: have %v bytes", ErrBadSize, len(data))
+	}
+
+	if len(opts.palette()) != 4 { // nolint: gomnd
+		return nil, fmt.Errorf("%w: have %v", ErrBadPalette, len(opts.palette()))
+	}
+
+	n := len(data) / TileSize
+	img := image.NewPaletted(opts.bounds(n), opts.palette())
+
+	for i := 0; i < n; i++ {
+		pixels := DecodeTile(data[i*TileSize : (i+1)*TileSize])
+		at := opts.position(i, TilesPerRow)
+
+		for y, row := range pixels {
+			for x, v := range row {
+				img.SetColorIndex(at.X+x, at.Y+y, v)
+			}
+		}
+	}
+
+	return img, nil
+}
+
+// Export draws the tiles of data in a sheet, the same way as Decode, and writes it to w as PNG.
+func Export(w io.Writer, data []byte, opts Options) error {
+	img, err := Decode(data, opts)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,129 @@
+package chr // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// The "½" tile, from the example of the nesdev pattern tables page.
+// nolint: gochecknoglobals
+var one = []byte{
+	0x41, 0xC2, 0x44, 0x48, 0x10, 0x20, 0x40, 0x80,
+	0x01, 0x02, 0x04, 0x08, 0x16, 0x21, 0x42, 0x87,
+}
+
+func TestDecodeTile(t *testing.T) {
+	t.Parallel()
+
+	want := [TileWidth][TileWidth]uint8{
+		{0, 1, 0, 0, 0, 0, 0, 3},
+		{1, 1, 0, 0, 0, 0, 3, 0},
+		{0, 1, 0, 0, 0, 3, 0, 0},
+		{0, 1, 0, 0, 3, 0, 0, 0},
+		{0, 0, 0, 3, 0, 2, 2, 0},
+		{0, 0, 3, 0, 0, 0, 0, 2},
+		{0, 3, 0, 0, 0, 0, 2, 0},
+		{3, 0, 0, 0, 0, 2, 2, 2},
+	}
+
+	if got := DecodeTile(one); got != want {
+		t.Errorf("DecodeTile() = %v, want %v", got, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	// Two pattern tables, where tile i is filled with the pixel value i%4
+	data := make([]byte, 2*PatternTableSize)
+	for i := 0; i < len(data)/TileSize; i++ {
+		for y := 0; y < TileWidth; y++ {
+			if i%4&1 != 0 {
+				data[i*TileSize+y] = 0xFF
+			}
+
+			if i%4&2 != 0 {
+				data[i*TileSize+TileWidth+y] = 0xFF
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want image.Rectangle
+		at   func(i int) image.Point
+	}{
+		{
+			name: "8x8",
+			want: image.Rect(0, 0, 128, 256),
+			at:   func(i int) image.Point { return image.Pt(i%16*8, i/16*8) },
+		},
+		{
+			name: "8x16",
+			opts: Options{Tall: true},
+			want: image.Rect(0, 0, 128, 256),
+			at:   func(i int) image.Point { return image.Pt(i/2%16*8, (i/32*2+i%2)*8) },
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			img, err := Decode(data, tt2.opts)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if img.Bounds() != tt2.want {
+				t.Fatalf("Decode() bounds = %v, want %v", img.Bounds(), tt2.want)
+			}
+
+			for i := 0; i < len(data)/TileSize; i++ {
+				at := tt2.at(i)
+				if got := img.ColorIndexAt(at.X+7, at.Y+7); got != uint8(i%4) {
+					t.Fatalf("Decode() tile %v at %v = %v, want %v", i, at, got, i%4)
+				}
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	palette := color.Palette{
+		color.RGBA{0x0F, 0x0F, 0x0F, 0xFF},
+		color.RGBA{0xFF, 0x00, 0x00, 0xFF},
+		color.RGBA{0x00, 0xFF, 0x00, 0xFF},
+		color.RGBA{0x00, 0x00, 0xFF, 0xFF},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, one, Options{Palette: palette}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+
+	if got := img.At(7, 0); got != palette[3] {
+		t.Errorf("Export() pixel = %v, want %v", got, palette[3])
+	}
+
+	if err := Export(&buf, one[:10], Options{}); !errors.Is(err, ErrBadSize) {
+		t.Errorf("Export() error = %v, want %v", err, ErrBadSize)
+	}
+
+	if err := Export(&buf, one, Options{Palette: palette[:2]}); !errors.Is(err, ErrBadPalette) {
+		t.Errorf("Export() error = %v, want %v", err, ErrBadPalette)
+	}
+}
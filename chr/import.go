@@ -0,0 +1,167 @@
+package chr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+var (
+	// ErrBadImageSize is returned when the image is not made of whole tiles.
+	ErrBadImageSize = errors.New("image size is not a multiple of the tile size")
+
+	// ErrColorClash is returned when two colors of a tile map to the same palette index.
+	ErrColorClash = errors.New("colors map to the same palette index")
+
+	// ErrTileOutOfRange is returned when the tiles of the image don't fit in the CHR data.
+	ErrTileOutOfRange = errors.New("tiles don't fit in the CHR data")
+)
+
+// ErrTooManyColors is returned when a tile of the image has more than the 4 colors a NES tile can have.
+// At is the top-left pixel of the tile in the image.
+type ErrTooManyColors struct {
+	Tile   int
+	At     image.Point
+	Colors int
+}
+
+func (e ErrTooManyColors) Error() string {
+	return fmt.Sprintf("tile %v at %v,%v has %v colors, want at most 4", e.Tile, e.At.X, e.At.Y, e.Colors)
+}
+
+// EncodeTile returns the 16-byte tile for the pixel values, 0 to 3, by row.
+func EncodeTile(pixels [TileWidth][TileWidth]uint8) []byte {
+	tile := make([]byte, TileSize)
+
+	for y, row := range pixels {
+		for x, v := range row {
+			bit := uint(TileWidth - 1 - x)
+			tile[y] |= (v & 1) << bit
+			tile[y+TileWidth] |= (v >> 1 & 1) << bit
+		}
+	}
+
+	return tile
+}
+
+// Encode is the reverse of Decode: it returns the tiles of the sheet img, by row.
+// The width of img gives the number of tiles per row.
+//
+// The pixels of a paletted image with at most 4 colors keep their index. Otherwise each color maps to
+// the same or the closest color of the palette, and fully transparent pixels map to color 0.
+// Encode returns ErrTooManyColors when a tile has more than 4 colors, and ErrColorClash
+// when two colors of a tile map to the same palette index.
+func Encode(img image.Image, opts Options) ([]byte, error) {
+	if len(opts.palette()) != 4 { // nolint: gomnd
+		return nil, fmt.Errorf("%w: have %v", ErrBadPalette, len(opts.palette()))
+	}
+
+	height := TileWidth
+	if opts.Tall {
+		height *= 2
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx()%TileWidth != 0 || bounds.Dy()%height != 0 {
+		return nil, fmt.Errorf("%w: have %vx%v, want multiples of %vx%v",
+			ErrBadImageSize, bounds.Dx(), bounds.Dy(), TileWidth, height)
+	}
+
+	perRow := bounds.Dx() / TileWidth
+	n := perRow * (bounds.Dy() / TileWidth)
+	data := make([]byte, 0, n*TileSize)
+
+	for i := 0; i < n; i++ {
+		at := bounds.Min.Add(opts.position(i, perRow))
+
+		pixels, err := tilePixels(img, at, opts.palette())
+		if err != nil {
+			if tooMany := (ErrTooManyColors{}); errors.As(err, &tooMany) {
+				tooMany.Tile, tooMany.At = i, at
+
+				return nil, tooMany
+			}
+
+			return nil, fmt.Errorf("tile %v at %v,%v: %w", i, at.X, at.Y, err)
+		}
+
+		data = append(data, EncodeTile(pixels)...)
+	}
+
+	return data, nil
+}
+
+// tilePixels maps the colors of the tile of img at the point at to pixel values.
+func tilePixels(img image.Image, at image.Point, palette color.Palette) ([TileWidth][TileWidth]uint8, error) {
+	var pixels [TileWidth][TileWidth]uint8
+
+	if paletted, ok := img.(*image.Paletted); ok && len(paletted.Palette) <= len(palette) {
+		for y := 0; y < TileWidth; y++ {
+			for x := 0; x < TileWidth; x++ {
+				pixels[y][x] = paletted.ColorIndexAt(at.X+x, at.Y+y)
+			}
+		}
+
+		return pixels, nil
+	}
+
+	colors := make(map[color.RGBA64]uint8)
+
+	for y := 0; y < TileWidth; y++ {
+		for x := 0; x < TileWidth; x++ {
+			colors[rgba64(img.At(at.X+x, at.Y+y), palette)] = 0
+		}
+	}
+
+	if len(colors) > len(palette) {
+		return pixels, ErrTooManyColors{Colors: len(colors)}
+	}
+
+	byIndex := make(map[uint8]color.RGBA64)
+
+	for c := range colors {
+		v := uint8(palette.Index(c))
+
+		if other, clash := byIndex[v]; clash {
+			return pixels, fmt.Errorf("%w: %v and %v both map to %v", ErrColorClash, other, c, v)
+		}
+
+		colors[c], byIndex[v] = v, c
+	}
+
+	for y := 0; y < TileWidth; y++ {
+		for x := 0; x < TileWidth; x++ {
+			pixels[y][x] = colors[rgba64(img.At(at.X+x, at.Y+y), palette)]
+		}
+	}
+
+	return pixels, nil
+}
+
+// rgba64 returns c, or color 0 of the palette if c is fully transparent.
+func rgba64(c color.Color, palette color.Palette) color.RGBA64 {
+	if _, _, _, a := c.RGBA(); a == 0 {
+		c = palette[0]
+	}
+
+	return color.RGBA64Model.Convert(c).(color.RGBA64)
+}
+
+// Import encodes the sheet img, the same way as Encode, and writes its tiles into the CHR data
+// starting at the given tile.
+func Import(data []byte, img image.Image, tile int, opts Options) error {
+	tiles, err := Encode(img, opts)
+	if err != nil {
+		return err
+	}
+
+	if start := tile * TileSize; tile < 0 || start+len(tiles) > len(data) {
+		return fmt.Errorf("%w: %v tiles from tile %v, have %v tiles",
+			ErrTileOutOfRange, len(tiles)/TileSize, tile, len(data)/TileSize)
+	}
+
+	copy(data[tile*TileSize:], tiles)
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package chr // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"testing"
+)
+
+// sheet returns 2 pattern tables of distinct tiles.
+func sheet() []byte {
+	data := make([]byte, 2*PatternTableSize)
+	for i := range data {
+		data[i] = byte(i*7 + i/TileSize)
+	}
+
+	return data
+}
+
+func TestEncode_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := sheet()
+
+	tests := []struct {
+		name    string
+		opts    Options
+		convert func(img *image.Paletted) image.Image
+	}{
+		{
+			name: "paletted png",
+			convert: func(img *image.Paletted) image.Image {
+				var buf bytes.Buffer
+				if err := png.Encode(&buf, img); err != nil {
+					t.Fatal(err)
+				}
+
+				decoded, err := png.Decode(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return decoded
+			},
+		},
+		{
+			name: "rgb",
+			convert: func(img *image.Paletted) image.Image {
+				rgba := image.NewRGBA(img.Bounds())
+				draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+
+				return rgba
+			},
+		},
+		{
+			name: "rgb 8x16 with a custom palette",
+			opts: Options{Tall: true, Palette: color.Palette{
+				color.RGBA{0x22, 0x22, 0x22, 0xFF},
+				color.RGBA{0xFF, 0x00, 0x00, 0xFF},
+				color.RGBA{0x00, 0xFF, 0x00, 0xFF},
+				color.RGBA{0x00, 0x00, 0xFF, 0xFF},
+			}},
+			convert: func(img *image.Paletted) image.Image {
+				rgba := image.NewRGBA(img.Bounds())
+				draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+
+				return rgba
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			img, err := Decode(data, tt2.opts)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			got, err := Encode(tt2.convert(img), tt2.opts)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Errorf("Encode() differs from the decoded data")
+			}
+		})
+	}
+}
+
+func TestEncode_errors(t *testing.T) {
+	t.Parallel()
+
+	// The second tile has 5 shades of gray
+	tooMany := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for x := 0; x < 5; x++ {
+		tooMany.Set(8+x, 0, color.Gray{Y: uint8(x * 0x33)})
+	}
+
+	clash := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	clash.Set(0, 0, color.Gray{Y: 0x00})
+	clash.Set(1, 0, color.Gray{Y: 0x10})
+
+	tests := []struct {
+		name    string
+		img     image.Image
+		wantErr error
+	}{
+		{
+			name:    "too many colors",
+			img:     tooMany,
+			wantErr: ErrTooManyColors{Tile: 1, At: image.Pt(8, 0), Colors: 5},
+		},
+		{
+			name:    "colors clash",
+			img:     clash,
+			wantErr: ErrColorClash,
+		},
+		{
+			name:    "partial tile",
+			img:     image.NewRGBA(image.Rect(0, 0, 12, 8)),
+			wantErr: ErrBadImageSize,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Encode(tt2.img, Options{}); !errors.Is(err, tt2.wantErr) {
+				t.Errorf("Encode() error = %v, want %v", err, tt2.wantErr)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	data := sheet()
+
+	img, err := Decode(data[:2*TileSize], Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first row of the sheet, with the first 2 tiles
+	row := img.SubImage(image.Rect(0, 0, 16, 8))
+
+	chrrom := make([]byte, len(data))
+	if err := Import(chrrom, row, 5, Options{}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if !bytes.Equal(chrrom[5*TileSize:7*TileSize], data[:2*TileSize]) || chrrom[7*TileSize] != 0 {
+		t.Errorf("Import() didn't write tiles 5 and 6")
+	}
+
+	if err := Import(chrrom, row, len(data)/TileSize-1, Options{}); !errors.Is(err, ErrTileOutOfRange) {
+		t.Errorf("Import() error = %v, want %v", err, ErrTileOutOfRange)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/drpaneas/ines"
+	"github.com/drpaneas/ines/chr"
+)
+
+func runChr(args []string, stdout io.Writer, stderr io.Writer) error {
+	subcommands := map[string]func([]string, io.Writer, io.Writer) error{
+		"export": runChrExport,
+		"import": runChrImport,
+	}
+
+	if len(args) == 0 || subcommands[args[0]] == nil {
+		fmt.Fprintf(stderr, "Usage: ines chr export|import [flags] <arguments>\n")
+
+		return errUsage
+	}
+
+	return subcommands[args[0]](args[1:], stdout, stderr)
+}
+
+func runChrExport(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("chr export", "<rom.nes> <out.png>", stderr)
+	palette := flags.String("palette", "000000,555555,aaaaaa,ffffff", "the 4 colors, as comma-separated RGB hex")
+	tall := flags.Bool("tall", false, "pair the tiles as 8x16 sprites")
+
+	if err := parseArgs(flags, args, 2); err != nil { // nolint: gomnd
+		return err
+	}
+
+	colors, err := parsePalette(*palette)
+	if err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if len(rom.CharacterRom) == 0 {
+		return errors.New("no CHR-ROM, the graphics are in CHR-RAM and written by the program")
+	}
+
+	var buf bytes.Buffer
+	if err := chr.Export(&buf, rom.CharacterRom, chr.Options{Palette: colors, Tall: *tall}); err != nil {
+		return err
+	}
+
+	if err := ines.Write(flags.Arg(1), buf.Bytes()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v (%v tiles)\n", flags.Arg(1), len(rom.CharacterRom)/chr.TileSize)
+
+	return nil
+}
+
+func runChrImport(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("chr import", "<rom.nes> <in.png>", stderr)
+	out := flags.String("o", "", "output file (required)")
+	tile := flags.Int("tile", 0, "first tile to overwrite")
+	palette := flags.String("palette", "000000,555555,aaaaaa,ffffff", "the 4 colors, as comma-separated RGB hex")
+	tall := flags.Bool("tall", false, "the tiles are paired as 8x16 sprites")
+
+	if err := parseArgs(flags, args, 2); err != nil { // nolint: gomnd
+		return err
+	}
+
+	if *out == "" {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	colors, err := parsePalette(*palette)
+	if err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	content, err := ines.Read(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	img, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to decode %v: %w", flags.Arg(1), err)
+	}
+
+	// Import writes in place, and CharacterRom shares its memory with the file content
+	rom.CharacterRom = append([]byte{}, rom.CharacterRom...)
+
+	if err := chr.Import(rom.CharacterRom, img, *tile, chr.Options{Palette: colors, Tall: *tall}); err != nil {
+		return err
+	}
+
+	b, err := ines.Encode(rom)
+	if err != nil {
+		return err
+	}
+
+	if err := ines.Write(*out, b); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v\n", *out)
+
+	return nil
+}
+
+// parsePalette parses 4 comma-separated RGB hex colors, like "000000,555555,aaaaaa,ffffff".
+func parsePalette(s string) (color.Palette, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 4 { // nolint: gomnd
+		return nil, fmt.Errorf("palette %q: want 4 colors, have %v", s, len(fields))
+	}
+
+	palette := make(color.Palette, 0, len(fields))
+
+	for _, field := range fields {
+		rgb, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(field), "#"), 16, 24)
+		if err != nil {
+			return nil, fmt.Errorf("palette %q: color %q is not RGB hex", s, field)
+		}
+
+		palette = append(palette, color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF})
+	}
+
+	return palette, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/drpaneas/ines"
+)
+
+func runConvert(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("convert", "<in.nes>", stderr)
+	to := flags.String("to", "nes2", "target format: nes2 or ines")
+	out := flags.String("o", "", "output file (required)")
+
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var losses []ines.Loss
+
+	switch *to {
+	case "nes2":
+		rom, err = ines.ToNES2(rom)
+	case "ines":
+		rom, losses, err = ines.ToINES(rom)
+	default:
+		return fmt.Errorf("unknown target format %q, want nes2 or ines", *to)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, loss := range losses {
+		fmt.Fprintf(stderr, "lost: %v\n", loss)
+	}
+
+	b, err := ines.Encode(rom)
+	if err != nil {
+		return err
+	}
+
+	if err := ines.Write(*out, b); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v (%v)\n", *out, rom.HeaderType)
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/drpaneas/ines/disasm"
+)
+
+// nolint: gomnd, cyclop, funlen
+func runDisasm(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("disasm", "<rom.nes>", stderr)
+	bank := flags.Int("bank", -1, "PRG-ROM bank to disassemble, all of them if negative")
+	bankSize := flags.Int("bank-size", 16, "PRG-ROM bank size in KiB: 8, 16 or 32")
+	origin := flags.String("origin", "", "CPU address of the first byte in hex, guessed from the bank if empty")
+	unofficial := flags.Bool("unofficial", false, "decode the unofficial opcodes")
+	trainer := flags.Bool("trainer", false, "disassemble the trainer rather than the PRG-ROM")
+
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	opts := disasm.Options{Unofficial: *unofficial}
+
+	if *trainer {
+		if len(rom.Trainer) == 0 {
+			return errors.New("no trainer")
+		}
+
+		opts.Origin = disasm.TrainerOrigin
+
+		return disasmBank(stdout, "Trainer", rom.Trainer, opts, *origin)
+	}
+
+	size := *bankSize * 1024
+	if size != 8192 && size != 16384 && size != 32768 {
+		return fmt.Errorf("bank size %v KiB, want 8, 16 or 32", *bankSize)
+	}
+
+	// Without bank switching, the whole PRG-ROM is mapped at once
+	if len(rom.ProgramRom) <= 32768 && *bank < 0 {
+		opts.Origin = disasm.Origin(len(rom.ProgramRom))
+
+		return disasmBank(stdout, "PRG-ROM", rom.ProgramRom, opts, *origin)
+	}
+
+	banks := (len(rom.ProgramRom) + size - 1) / size
+	if *bank >= banks {
+		return fmt.Errorf("bank %v out of range, have %v banks of %v KiB", *bank, banks, *bankSize)
+	}
+
+	for i := 0; i < banks; i++ {
+		if *bank >= 0 && i != *bank {
+			continue
+		}
+
+		// The last bank is usually the one fixed at the top, with the vectors
+		opts.Origin = 0x8000
+		if i == banks-1 {
+			opts.Origin = uint16(0x10000 - size)
+		}
+
+		end := (i + 1) * size
+		if end > len(rom.ProgramRom) {
+			end = len(rom.ProgramRom)
+		}
+
+		title := fmt.Sprintf("PRG-ROM bank %v", i)
+		if err := disasmBank(stdout, title, rom.ProgramRom[i*size:end], opts, *origin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// disasmBank writes the listing of b, at the origin given in hex if it's not empty.
+func disasmBank(w io.Writer, title string, b []byte, opts disasm.Options, origin string) error {
+	if origin != "" {
+		address, err := strconv.ParseUint(origin, 16, 16)
+		if err != nil {
+			return fmt.Errorf("origin %q is not a hex address", origin)
+		}
+
+		opts.Origin = uint16(address)
+	}
+
+	fmt.Fprintf(w, "; %v, %v bytes\n", title, len(b))
+
+	if err := disasm.Listing(w, b, opts); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w)
+
+	return err
+}
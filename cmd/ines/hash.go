@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/drpaneas/ines"
+)
+
+// The formats of hash. The databases identify a game by the headerless data, and the NES 2.0 DB by the chips too.
+// The checksum files are about the file as it is on disk, so that the usual tools can check it.
+const (
+	hashDAT       = "dat"       // a Logiqx <rom> entry, as in the No-Intro and GoodNES DATs
+	hashNES20DB   = "nes20db"   // the <trainer>, <prgrom>, <chrrom> and <rom> entries of the NES 2.0 DB
+	hashSFV       = "sfv"       // the CRC32 of the file
+	hashMD5Sum    = "md5sum"    // the MD5 of the file, as md5sum prints it
+	hashSHA1Sum   = "sha1sum"   // the SHA-1 of the file, as sha1sum prints it
+	hashSHA256Sum = "sha256sum" // the SHA-256 of the file, as sha256sum prints it
+)
+
+func runHash(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("hash", "<rom.nes>", stderr)
+	format := flags.String("format", hashDAT, "output format: dat, nes20db, sfv, md5sum, sha1sum or sha256sum")
+
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	hashes := ines.Hash(rom)
+	name := filepath.Base(flags.Arg(0))
+
+	switch *format {
+	case hashDAT:
+		f := ines.NewDATRom(name, hashes.Headerless)
+		fmt.Fprintf(stdout, "<rom name=\"%v\" size=\"%v\" crc=\"%v\" md5=\"%v\" sha1=\"%v\"/>\n",
+			escapeXML(f.Name), f.Size, f.CRC, f.MD5, f.SHA1)
+	case hashNES20DB:
+		fmt.Fprintf(stdout, "<!-- %v -->\n", escapeXML(name))
+
+		for _, chip := range []struct {
+			element string
+			digest  ines.Digest
+			data    []byte
+		}{
+			{"trainer", hashes.Trainer, rom.Trainer},
+			{"prgrom", hashes.PRG, rom.ProgramRom},
+			{"chrrom", hashes.CHR, rom.CharacterRom},
+		} {
+			if chip.digest.Size != 0 {
+				fmt.Fprintf(stdout, "<%v size=\"%v\" crc32=\"%08X\" sha1=\"%X\" sum16=\"%04X\"/>\n",
+					chip.element, chip.digest.Size, chip.digest.CRC32, chip.digest.SHA1, sum16(chip.data))
+			}
+		}
+
+		d := hashes.Headerless
+		fmt.Fprintf(stdout, "<rom size=\"%v\" crc32=\"%08X\" sha1=\"%X\"/>\n", d.Size, d.CRC32, d.SHA1)
+	case hashSFV:
+		fmt.Fprintf(stdout, "%v %08X\n", name, hashes.File.CRC32)
+	case hashMD5Sum:
+		fmt.Fprintf(stdout, "%x  %v\n", hashes.File.MD5, name)
+	case hashSHA1Sum:
+		fmt.Fprintf(stdout, "%x  %v\n", hashes.File.SHA1, name)
+	case hashSHA256Sum:
+		fmt.Fprintf(stdout, "%x  %v\n", hashes.File.SHA256, name)
+	default:
+		return fmt.Errorf("unknown format %q, want dat, nes20db, sfv, md5sum, sha1sum or sha256sum", *format)
+	}
+
+	return nil
+}
+
+// sum16 returns the sum of the bytes of b, modulo 65536, which the NES 2.0 DB lists for each chip.
+func sum16(b []byte) uint16 {
+	var sum uint16
+
+	for _, v := range b {
+		sum += uint16(v)
+	}
+
+	return sum
+}
+
+// escapeXML escapes s for an XML attribute value.
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+
+	_ = xml.EscapeText(&buf, []byte(s)) // writing to a bytes.Buffer never fails
+
+	return buf.String()
+}
@@ -0,0 +1,81 @@
+package main // nolint: testpackage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/ines"
+)
+
+func TestRun_hash(t *testing.T) {
+	t.Parallel()
+
+	db, err := ines.Read("../../testdata/nes20db.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The No-Intro entry of the demo, and the line of the NES 2.0 DB for its headerless data
+	const datEntry = `<rom name="thewit-demo.nes" size="40960" crc="730E70AC" md5="24F97A313B03BA9A3BEE578AD7015F19" ` +
+		`sha1="B15B26B07CF13475CAE3D61E463E93F39632250F"/>`
+
+	nes20dbROM := `<rom size="40960" crc32="730E70AC" sha1="B15B26B07CF13475CAE3D61E463E93F39632250F"/>`
+	if !bytes.Contains(db, []byte(nes20dbROM)) {
+		t.Fatalf("testdata/nes20db.xml has no %v", nes20dbROM)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "dat", want: datEntry},
+		{format: "nes20db", want: nes20dbROM},
+		{format: "sfv", want: "thewit-demo.nes 947C6A72"},
+		{format: "md5sum", want: "cb3b323e31cc9e14d80551f9c7651ca5  thewit-demo.nes"},
+		{format: "sha1sum", want: "034dc2e0b987093e4bff41b83707d39b5c2a0d7e  thewit-demo.nes"},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.format, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			if code := run([]string{"hash", "-format", tt2.format, demo}, &stdout, &stderr); code != 0 {
+				t.Fatalf("hash = %v, stderr:\n%v", code, stderr.String())
+			}
+
+			lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+			if got := lines[len(lines)-1]; got != tt2.want {
+				t.Errorf("hash -format %v = %v, want %v", tt2.format, got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestRun_hashMatchesDAT(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"hash", demo}, &stdout, &stderr); code != 0 {
+		t.Fatalf("hash = %v, stderr:\n%v", code, stderr.String())
+	}
+
+	dat, err := ines.ParseDAT(strings.NewReader(`<datafile><game name="The Wit (Demo)">` + stdout.String() +
+		`</game></datafile>`))
+	if err != nil {
+		t.Fatalf("hash printed an entry ParseDAT can't read: %v", err)
+	}
+
+	rom, err := decodeFile(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if game, _, ok := dat.Match(rom); !ok || game.Name != "The Wit (Demo)" {
+		t.Errorf("Match() = %q, %v, want the game of the entry hash printed", game.Name, ok)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/drpaneas/ines"
+)
+
+// romInfo is what info prints, in the order of the table.
+type romInfo struct {
+	File            string        `json:"file"`
+	Format          string        `json:"format"`
+	Mapper          int           `json:"mapper"`
+	MapperName      string        `json:"mapperName,omitempty"`
+	SubMapper       int           `json:"subMapper"` // -1 for iNES 1.0, which has no submapper
+	SubMapperName   string        `json:"subMapperName"`
+	Mirroring       string        `json:"mirroring"`
+	HasBattery      bool          `json:"hasBattery"`
+	ConsoleType     string        `json:"consoleType"`
+	TVSystem        string        `json:"tvSystem"`
+	CPUPPUTiming    string        `json:"cpuPpuTiming"`
+	VsSystemPPU     string        `json:"vsSystemPpu,omitempty"`
+	VsSystemType    string        `json:"vsSystemType,omitempty"`
+	ExpansionDevice string        `json:"expansionDevice"`
+	PRGRAM          int           `json:"prgRam"`
+	PRGNVRAM        int           `json:"prgNvram"`
+	CHRRAM          int           `json:"chrRam"`
+	CHRNVRAM        int           `json:"chrNvram"`
+	Sections        []infoSection `json:"sections"`
+	Vectors         *infoVectors  `json:"vectors,omitempty"`
+}
+
+type infoSection struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+	Size   int    `json:"size"`
+}
+
+type infoVectors struct {
+	NMI   uint16 `json:"nmi"`
+	Reset uint16 `json:"reset"`
+	IRQ   uint16 `json:"irq"`
+}
+
+func runInfo(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("info", "<rom.nes>", stderr)
+	asJSON := flags.Bool("json", false, "print JSON rather than a table")
+
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	info := newRomInfo(flags.Arg(0), rom)
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(info)
+	}
+
+	return writeInfo(stdout, info)
+}
+
+func newRomInfo(path string, rom ines.Rom) romInfo {
+	info := romInfo{
+		File:            path,
+		Format:          rom.HeaderType.String(),
+		Mapper:          rom.Mapper,
+		SubMapper:       rom.SubMapper,
+		SubMapperName:   ines.DescribeSubMapper(rom.Mapper, rom.SubMapper).String(),
+		Mirroring:       rom.Mirroring.String(),
+		HasBattery:      rom.HasBattery,
+		ConsoleType:     rom.ConsoleType.String(),
+		TVSystem:        rom.TVSystem.String(),
+		CPUPPUTiming:    rom.CPUPPUTiming.String(),
+		ExpansionDevice: rom.ExpansionDevice.String(),
+		PRGRAM:          len(rom.ProgramRAM),
+		PRGNVRAM:        len(rom.ProgramNVRam),
+		CHRRAM:          len(rom.CharacterRAM),
+		CHRNVRAM:        len(rom.CharacterNVRam),
+	}
+
+	if mapper, ok := ines.LookupMapper(rom.Mapper, rom.SubMapper); ok {
+		info.MapperName = mapper.Name
+	}
+
+	if rom.ConsoleType == ines.ConsoleTypeVs && rom.VsSystemPPU != ines.VsPPUTypeUnknown {
+		info.VsSystemPPU, info.VsSystemType = rom.VsSystemPPU.String(), rom.VsSystemType.String()
+	}
+
+	for _, region := range rom.Layout() {
+		info.Sections = append(info.Sections, infoSection{Name: region.Name, Offset: region.Offset, Size: region.Size})
+	}
+
+	if vectors, err := ines.ReadVectors(rom); err == nil {
+		info.Vectors = &infoVectors{NMI: vectors.NMI.Address, Reset: vectors.Reset.Address, IRQ: vectors.IRQ.Address}
+	}
+
+	return info
+}
+
+func writeInfo(w io.Writer, info romInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) // nolint: gomnd
+
+	mapper := strconv.Itoa(info.Mapper)
+	if info.MapperName != "" {
+		mapper += " (" + info.MapperName + ")"
+	}
+
+	rows := [][2]string{
+		{"File", info.File},
+		{"Format", info.Format},
+		{"Mapper", mapper},
+		{"Submapper", info.SubMapperName},
+		{"Mirroring", info.Mirroring},
+		{"Battery", strconv.FormatBool(info.HasBattery)},
+		{"Console type", info.ConsoleType},
+		{"TV system", info.TVSystem},
+		{"CPU/PPU timing", info.CPUPPUTiming},
+	}
+
+	if info.VsSystemPPU != "" {
+		rows = append(rows, [2]string{"Vs. PPU", info.VsSystemPPU}, [2]string{"Vs. hardware", info.VsSystemType})
+	}
+
+	rows = append(rows,
+		[2]string{"Expansion device", info.ExpansionDevice},
+		[2]string{"PRG-RAM", kib(info.PRGRAM)},
+		[2]string{"PRG-NVRAM", kib(info.PRGNVRAM)},
+		[2]string{"CHR-RAM", kib(info.CHRRAM)},
+		[2]string{"CHR-NVRAM", kib(info.CHRNVRAM)},
+	)
+
+	if v := info.Vectors; v != nil {
+		rows = append(rows,
+			[2]string{"NMI vector", fmt.Sprintf("$%04X", v.NMI)},
+			[2]string{"RESET vector", fmt.Sprintf("$%04X", v.Reset)},
+			[2]string{"IRQ vector", fmt.Sprintf("$%04X", v.IRQ)},
+		)
+	}
+
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%v\t%v\n", row[0], row[1])
+	}
+
+	for _, section := range info.Sections {
+		fmt.Fprintf(tw, "%v\t%v\tat %#06x\n", section.Name, kib(section.Size), section.Offset)
+	}
+
+	return tw.Flush()
+}
+
+// kib formats a size in KiB, with the number of bytes when it isn't a whole number of KiB.
+// nolint: gomnd
+func kib(size int) string {
+	if size%1024 == 0 {
+		return fmt.Sprintf("%v KiB", size/1024)
+	}
+
+	return fmt.Sprintf("%.2f KiB (%v bytes)", float64(size)/1024, size)
+}
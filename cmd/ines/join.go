@@ -0,0 +1,215 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/drpaneas/ines"
+)
+
+// joinFlags are the header fields of join, for dumps which come without a header.
+type joinFlags struct {
+	format    string
+	mapper    int
+	subMapper int
+	mirroring string
+	battery   bool
+	tv        string
+	prgRAM    int
+	prgNVRAM  int
+	chrRAM    int
+	chrNVRAM  int
+}
+
+// nolint: cyclop, funlen
+func runJoin(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("join", "", stderr)
+	out := flags.String("o", "", "output file (required)")
+	prg := flags.String("prg", "", "PRG-ROM dump (required)")
+	chr := flags.String("chr", "", "CHR-ROM dump, none for CHR-RAM")
+	trainer := flags.String("trainer", "", "512-byte trainer")
+	misc := flags.String("misc", "", "Miscellaneous ROM, NES 2.0 only")
+	inst := flags.String("inst", "", "PlayChoice-10 INST-ROM")
+	promData := flags.String("prom-data", "", "PlayChoice-10 PROM data")
+	promCounterOut := flags.String("prom-counterout", "", "PlayChoice-10 PROM CounterOut")
+	title := flags.String("title", "", "title block to append")
+	header := flags.String("header", "", "16-byte header to take the fields from, rather than from the flags")
+
+	var f joinFlags
+
+	flags.StringVar(&f.format, "format", "nes2", "header format: nes2 or ines")
+	flags.IntVar(&f.mapper, "mapper", 0, "mapper number")
+	flags.IntVar(&f.subMapper, "submapper", 0, "submapper number, NES 2.0 only")
+	flags.StringVar(&f.mirroring, "mirroring", "h", "nametable mirroring: h, v or 4 for four-screen")
+	flags.BoolVar(&f.battery, "battery", false, "battery-backed PRG-RAM")
+	flags.StringVar(&f.tv, "tv", "ntsc", "TV system: ntsc, pal, multi or dendy")
+	flags.IntVar(&f.prgRAM, "prg-ram", 0, "PRG-RAM size in bytes, NES 2.0 only")
+	flags.IntVar(&f.prgNVRAM, "prg-nvram", 0, "PRG-NVRAM size in bytes, NES 2.0 only")
+	flags.IntVar(&f.chrRAM, "chr-ram", -1, "CHR-RAM size in bytes, 8192 without -chr if negative, NES 2.0 only")
+	flags.IntVar(&f.chrNVRAM, "chr-nvram", 0, "CHR-NVRAM size in bytes, NES 2.0 only")
+
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+
+	if *out == "" || *prg == "" {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	var rom ines.Rom
+
+	for _, part := range []struct {
+		path string
+		data *[]byte
+	}{
+		{*prg, &rom.ProgramRom}, {*chr, &rom.CharacterRom}, {*trainer, &rom.Trainer}, {*misc, &rom.MiscRom},
+		{*title, &rom.Title}, {*inst, &rom.PlayChoiceInstRom}, {*promData, &rom.PlayChoicePROMData},
+		{*promCounterOut, &rom.PlayChoicePROMCounterOut},
+	} {
+		if part.path == "" {
+			continue
+		}
+
+		b, err := ines.Read(part.path)
+		if err != nil {
+			return err
+		}
+
+		*part.data = b
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if *header != "" {
+		b, err = joinWithHeader(*header, rom)
+	} else {
+		b, err = joinWithFlags(f, rom)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Decoding the result checks that the header describes the dumps
+	joined, err := ines.Decode(b)
+	if err != nil {
+		return err
+	}
+
+	for _, diagnostic := range ines.Validate(joined) {
+		fmt.Fprintf(stderr, "%v:%v\n", *out, diagnostic)
+	}
+
+	if err := ines.Write(*out, b); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v (%v)\n", *out, joined.HeaderType)
+
+	return nil
+}
+
+// joinWithHeader lays out the sections of rom after the header read from path, as split writes it.
+func joinWithHeader(path string, rom ines.Rom) ([]byte, error) {
+	header, err := ines.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(header) != 16 { // nolint: gomnd
+		return nil, fmt.Errorf("header %v has %v bytes, want 16", path, len(header))
+	}
+
+	b := append(header, rom.Trainer...)
+	b = append(b, rom.ProgramRom...)
+	b = append(b, rom.CharacterRom...)
+	b = append(b, rom.PlayChoiceInstRom...)
+	b = append(b, rom.PlayChoicePROMData...)
+	b = append(b, rom.PlayChoicePROMCounterOut...)
+	b = append(b, rom.MiscRom...)
+
+	return append(b, rom.Title...), nil
+}
+
+// joinWithFlags encodes rom with the header fields given by f.
+// nolint: cyclop
+func joinWithFlags(f joinFlags, rom ines.Rom) ([]byte, error) {
+	rom.Mapper, rom.SubMapper, rom.HasBattery = f.mapper, f.subMapper, f.battery
+	rom.VsSystemPPU, rom.VsSystemType = ines.VsPPUTypeUnknown, ines.VsSystemTypeUnknown
+
+	switch f.mirroring {
+	case "h":
+		rom.Mirroring = ines.MirroringHorizontal
+	case "v":
+		rom.Mirroring = ines.MirroringVertical
+	case "4":
+		rom.Mirroring = ines.MirroringFourScreen
+	default:
+		return nil, fmt.Errorf("unknown mirroring %q, want h, v or 4", f.mirroring)
+	}
+
+	switch f.tv {
+	case "ntsc":
+		rom.TVSystem = ines.TVSystemNTSC
+	case "pal":
+		rom.TVSystem = ines.TVSystemPAL
+	case "multi":
+		rom.TVSystem = ines.TVSystemMultiRegion
+	case "dendy":
+		rom.TVSystem = ines.TVSystemDendy
+	default:
+		return nil, fmt.Errorf("unknown TV system %q, want ntsc, pal, multi or dendy", f.tv)
+	}
+
+	if f.prgRAM < 0 || f.prgNVRAM < 0 || f.chrNVRAM < 0 {
+		return nil, errors.New("negative RAM size")
+	}
+
+	chrRAM := f.chrRAM
+	if chrRAM < 0 {
+		chrRAM = 0
+		if len(rom.CharacterRom) == 0 {
+			chrRAM = 8192
+		}
+	}
+
+	if len(rom.PlayChoiceInstRom) != 0 {
+		rom.ConsoleType = ines.ConsoleTypePlayChoice
+	}
+
+	switch f.format {
+	case "nes2":
+		if len(rom.PlayChoiceInstRom) != 0 && len(rom.MiscRom) != 0 {
+			return nil, errors.New("give either -misc or the PlayChoice-10 data, which NES 2.0 keeps in the same place")
+		}
+
+		// NES 2.0 keeps the PlayChoice-10 data in the Miscellaneous ROM
+		rom.HeaderType = ines.FormatNES2
+		if len(rom.PlayChoiceInstRom) != 0 {
+			rom.MiscRom = append(append(rom.PlayChoiceInstRom, rom.PlayChoicePROMData...), rom.PlayChoicePROMCounterOut...)
+			rom.PlayChoiceInstRom, rom.PlayChoicePROMData, rom.PlayChoicePROMCounterOut = nil, nil, nil
+		}
+
+		rom.CPUPPUTiming = ines.CPUPPUTiming(rom.TVSystem)
+		rom.ProgramRAM, rom.ProgramNVRam = make([]byte, f.prgRAM), make([]byte, f.prgNVRAM)
+		rom.CharacterRAM, rom.CharacterNVRam = make([]byte, chrRAM), make([]byte, f.chrNVRAM)
+	case "ines":
+		if f.subMapper != 0 || f.prgRAM != 0 || f.prgNVRAM != 0 || f.chrRAM > 0 || f.chrNVRAM != 0 || len(rom.MiscRom) != 0 {
+			return nil, errors.New("iNES 1.0 has no submapper, RAM sizes or Miscellaneous ROM, use -format nes2")
+		}
+
+		rom.HeaderType = ines.FormatINES
+		rom.SubMapper = ines.SubMapperUnspecified
+		rom.CPUPPUTiming = ines.CPUPPUTimingUnknown
+	default:
+		return nil, fmt.Errorf("unknown format %q, want nes2 or ines", f.format)
+	}
+
+	return ines.Encode(rom)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/drpaneas/ines"
+)
+
+func runLint(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("lint", "<rom.nes>", stderr)
+	strict := flags.Bool("strict", false, "fail on warnings too")
+
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	failOn := ines.SeverityError
+	if *strict {
+		failOn = ines.SeverityWarning
+	}
+
+	failed := 0
+
+	for _, diagnostic := range ines.Validate(rom) {
+		fmt.Fprintf(stdout, "%v:%v\n", flags.Arg(0), diagnostic)
+
+		if diagnostic.Severity >= failOn {
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%v: %v problems at %v level or above", flags.Arg(0), failed, failOn)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// Command ines inspects and edits iNES 1.0 and NES 2.0 files.
+//
+// Usage:
+//
+//	ines <command> [flags] <arguments>
+//
+// Run 'ines help' for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/drpaneas/ines"
+)
+
+// command is a subcommand of ines.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout io.Writer, stderr io.Writer) error
+}
+
+// errUsage is returned by commands called with the wrong arguments, after printing their usage.
+var errUsage = errors.New("usage")
+
+func commands() []command {
+	return []command{
+		{name: "info", usage: "print the header fields and the layout of the file", run: runInfo},
+		{name: "split", usage: "write the sections to separate files, with a manifest", run: runSplit},
+		{name: "join", usage: "build a file out of raw PRG-ROM and CHR-ROM dumps", run: runJoin},
+		{name: "convert", usage: "convert between iNES 1.0 and NES 2.0", run: runConvert},
+		{name: "lint", usage: "check the header against the data", run: runLint},
+		{name: "hash", usage: "print the CRC32, MD5, SHA-1 and SHA-256 of each section", run: runHash},
+		{name: "patch", usage: "apply or revert an IPS, BPS or UPS patch and validate the result", run: runPatch},
+		{name: "diff", usage: "create an IPS, BPS or UPS patch between two files", run: runDiff},
+		{name: "chr", usage: "export the CHR-ROM tiles as PNG, or import them back", run: runChr},
+		{name: "disasm", usage: "disassemble the PRG-ROM or the trainer", run: runDisasm},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command given by args and returns the exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+
+		if len(args) == 0 {
+			return 2 // nolint: gomnd
+		}
+
+		return 0
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		err := cmd.run(args[1:], stdout, stderr)
+
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+			return 2 // nolint: gomnd
+		default:
+			fmt.Fprintf(stderr, "ines %v: %v\n", cmd.name, err)
+
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "ines: unknown command %q\n", args[0])
+	usage(stderr)
+
+	return 2 // nolint: gomnd
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: ines <command> [flags] <arguments>\n\nCommands:\n")
+
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10v %v\n", cmd.name, cmd.usage)
+	}
+}
+
+// newFlagSet returns a flag set for a command, which prints its usage to stderr.
+func newFlagSet(name string, arguments string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: ines %v [flags] %v\n", name, arguments)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// parseArgs parses the flags of a command and checks the number of its positional arguments.
+// The flag set has printed the error and the usage already when the flags are wrong.
+func parseArgs(flags *flag.FlagSet, args []string, want int) error {
+	switch err := flags.Parse(args); {
+	case errors.Is(err, flag.ErrHelp):
+		return err
+	case err != nil:
+		return errUsage
+	}
+
+	if flags.NArg() != want {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	return nil
+}
+
+// decodeFile reads and decodes the ROM at path.
+func decodeFile(path string) (ines.Rom, error) {
+	content, err := ines.Read(path)
+	if err != nil {
+		return ines.Rom{}, err
+	}
+
+	rom, err := ines.Decode(content)
+	if err != nil {
+		return ines.Rom{}, fmt.Errorf("failed to decode %v: %w", path, err)
+	}
+
+	return rom, nil
+}
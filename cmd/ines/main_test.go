@@ -0,0 +1,74 @@
+package main // nolint: testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_exitCode(t *testing.T) {
+	t.Parallel()
+
+	notROM := writeROM(t, "not.nes", []byte("not an iNES file"))
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no command", args: nil, want: 2},
+		{name: "help", args: []string{"help"}, want: 0},
+		{name: "unknown command", args: []string{"frobnicate"}, want: 2},
+		{name: "missing argument", args: []string{"info"}, want: 2},
+		{name: "unknown flag", args: []string{"info", "-frobnicate", demo}, want: 2},
+		{name: "missing file", args: []string{"info", filepath.Join(t.TempDir(), "missing.nes")}, want: 1},
+		{name: "file without header", args: []string{"info", notROM}, want: 1},
+		{name: "info", args: []string{"info", demo}, want: 0},
+		{name: "lint", args: []string{"lint", demo}, want: 0},
+		{name: "hash", args: []string{"hash", demo}, want: 0},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			if got := run(tt2.args, &stdout, &stderr); got != tt2.want {
+				t.Errorf("run(%q) = %v, want %v, stderr:\n%v", tt2.args, got, tt2.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_infoJSON(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"info", "--json", demo}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run() = %v, stderr:\n%v", code, stderr.String())
+	}
+
+	var info romInfo
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		t.Fatalf("info --json printed invalid JSON: %v", err)
+	}
+
+	want := []infoSection{{"Header", 0, 16}, {"PRG-ROM", 16, 32768}, {"CHR-ROM", 32784, 8192}}
+	if info.Format != "iNES 1.0" || info.Mapper != 0 || len(info.Sections) != len(want) {
+		t.Fatalf("info --json = %+v", info)
+	}
+
+	for i, section := range info.Sections {
+		if section != want[i] {
+			t.Errorf("info --json section %v = %+v, want %+v", i, section, want[i])
+		}
+	}
+
+	if info.Vectors == nil {
+		t.Error("info --json has no vectors")
+	}
+}
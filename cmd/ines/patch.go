@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/drpaneas/ines"
+)
+
+func runPatch(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("patch", "<rom.nes> <patch>", stderr)
+	out := flags.String("o", "", "output file (required)")
+	headerless := flags.Bool("headerless", false, "apply the patch to the file without its header")
+	revert := flags.Bool("revert", false, "undo a UPS patch on the patched file")
+
+	if err := parseArgs(flags, args, 2); err != nil { // nolint: gomnd
+		return err
+	}
+
+	if *out == "" {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	patch, err := ines.Read(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	apply := ines.ApplyPatch
+
+	switch {
+	case *revert && *headerless:
+		apply = ines.RevertPatchHeaderless
+	case *revert:
+		apply = ines.RevertPatch
+	case *headerless:
+		apply = ines.ApplyPatchHeaderless
+	}
+
+	rom, diagnostics, err := apply(rom, patch)
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(stderr, "%v:%v\n", *out, diagnostic)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b, err := ines.Encode(rom)
+	if err != nil {
+		return err
+	}
+
+	if err := ines.Write(*out, b); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v\n", *out)
+
+	return nil
+}
+
+func runDiff(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("diff", "<original.nes> <modified.nes>", stderr)
+	out := flags.String("o", "", "output patch file (required)")
+	format := flags.String("format", "ips", "patch format: ips, bps or ups")
+	headerless := flags.Bool("headerless", false, "leave the header out of the patch")
+
+	if err := parseArgs(flags, args, 2); err != nil { // nolint: gomnd
+		return err
+	}
+
+	if *out == "" {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	original, err := ines.Read(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	modified, err := ines.Read(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	if *headerless {
+		original, modified = headerlessData(original), headerlessData(modified)
+	}
+
+	var patch []byte
+
+	switch *format {
+	case "ips":
+		patch, err = ines.CreateIPS(original, modified)
+	case "bps":
+		patch = ines.CreateBPS(original, modified)
+	case "ups":
+		patch = ines.CreateUPS(original, modified)
+	default:
+		return fmt.Errorf("unknown patch format %q, want ips, bps or ups", *format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := ines.Write(*out, patch); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v (%v bytes)\n", *out, len(patch))
+
+	return nil
+}
+
+// headerlessData returns b without its iNES header, if it has one.
+func headerlessData(b []byte) []byte {
+	if _, err := ines.DetectFormat(b); err != nil {
+		return b
+	}
+
+	return b[16:] // nolint: gomnd
+}
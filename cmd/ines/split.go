@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpaneas/ines"
+)
+
+// manifestFile is the name of the file which split writes next to the sections.
+const manifestFile = "manifest.json"
+
+// sectionFiles are the names of the files which split writes the regions of the layout to.
+// Gaps and overlaps don't happen in a decoded ROM, so they have none.
+var sectionFiles = map[string]string{ // nolint: gochecknoglobals
+	ines.RegionHeader:         "header.bin",
+	ines.RegionTrainer:        "trainer.bin",
+	ines.RegionPRGROM:         "PRGROM.bin",
+	ines.RegionCHRROM:         "CHRROM.bin",
+	ines.RegionMiscROM:        "MISCROM.bin",
+	ines.RegionInstROM:        "INSTROM.bin",
+	ines.RegionPROMData:       "PROMDATA.bin",
+	ines.RegionPROMCounterOut: "PROMCOUNTEROUT.bin",
+	ines.RegionTitle:          "title.bin",
+	ines.RegionUnaccounted:    "title.bin",
+}
+
+// manifest describes the files written by split.
+type manifest struct {
+	Source   string          `json:"source"`
+	Format   string          `json:"format"`
+	Sections []manifestEntry `json:"sections"`
+}
+
+type manifestEntry struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Offset int    `json:"offset"`
+	Size   int    `json:"size"`
+	CRC32  string `json:"crc32"`
+}
+
+func runSplit(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("split", "<rom.nes>", stderr)
+	out := flags.String("o", "", "output directory, named after the ROM if empty")
+
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+
+	rom, err := decodeFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	dir := *out
+	if dir == "" {
+		dir = strings.TrimSuffix(filepath.Base(flags.Arg(0)), filepath.Ext(flags.Arg(0)))
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil { // nolint: gomnd
+		return err
+	}
+
+	m := manifest{Source: filepath.Base(flags.Arg(0)), Format: rom.HeaderType.String()}
+	file := append(append([]byte{}, rom.Header[:]...), rom.Headerless...)
+
+	for _, region := range rom.Layout() {
+		name, ok := sectionFiles[region.Name]
+		if !ok {
+			return fmt.Errorf("%v has no file for the %v at %#06x", flags.Arg(0), region.Name, region.Offset)
+		}
+
+		data := file[region.Offset:region.End()]
+		if err := ines.Write(filepath.Join(dir, name), data); err != nil {
+			return err
+		}
+
+		m.Sections = append(m.Sections, manifestEntry{
+			Name:   region.Name,
+			File:   name,
+			Offset: region.Offset,
+			Size:   region.Size,
+			CRC32:  fmt.Sprintf("%08X", crc32.ChecksumIEEE(data)),
+		})
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ines.Write(filepath.Join(dir, manifestFile), append(b, '\n')); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %v sections to %v\n", len(m.Sections), dir)
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package main // nolint: testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/drpaneas/ines"
+)
+
+const demo = "../../testdata/thewit-demo.nes"
+
+// writeROM writes b to a file in a new temporary directory and returns its path.
+func writeROM(t *testing.T, name string, b []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := ines.Write(path, b); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// playChoiceROM returns an iNES 1.0 PlayChoice-10 file with its PROM and a title block.
+func playChoiceROM() []byte {
+	b := []byte{78, 69, 83, 26, 1, 1, 0, 0x02, 0, 0, 0, 0, 0, 0, 0, 0}
+	b = append(b, bytes.Repeat([]byte{1}, 16384)...)
+	b = append(b, bytes.Repeat([]byte{2}, 8192)...)
+	b = append(b, bytes.Repeat([]byte{3}, 8192)...)
+	b = append(b, bytes.Repeat([]byte{4}, 16)...)
+	b = append(b, 0, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF)
+
+	return append(b, bytes.Repeat([]byte{'T'}, 128)...)
+}
+
+// joinFlagOf is the join flag which reads back each file written by split.
+var joinFlagOf = map[string]string{ // nolint: gochecknoglobals
+	"header.bin":         "-header",
+	"trainer.bin":        "-trainer",
+	"PRGROM.bin":         "-prg",
+	"CHRROM.bin":         "-chr",
+	"MISCROM.bin":        "-misc",
+	"INSTROM.bin":        "-inst",
+	"PROMDATA.bin":       "-prom-data",
+	"PROMCOUNTEROUT.bin": "-prom-counterout",
+	"title.bin":          "-title",
+}
+
+func TestRun_splitJoin(t *testing.T) {
+	t.Parallel()
+
+	original, err := ines.Read(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nes2PlayChoice := append([]byte{78, 69, 83, 26, 1, 1, 0, 0x0A, 0, 0, 0, 0, 0, 0, 1, 0}, playChoiceROM()[16:]...)
+
+	tests := []struct {
+		name         string
+		b            []byte
+		withoutFlags []string // the flags which rebuild the header when join doesn't get it
+	}{
+		{name: "ines 1.0", b: original, withoutFlags: []string{"-format", "ines", "-mirroring", "v"}},
+		{name: "playchoice-10", b: playChoiceROM(), withoutFlags: []string{"-format", "ines"}},
+		{name: "nes 2.0 playchoice-10", b: nes2PlayChoice},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeROM(t, "rom.nes", tt2.b)
+			dir := filepath.Join(filepath.Dir(path), "sections")
+
+			var stdout, stderr bytes.Buffer
+
+			if code := run([]string{"split", "-o", dir, path}, &stdout, &stderr); code != 0 {
+				t.Fatalf("split = %v, stderr:\n%v", code, stderr.String())
+			}
+
+			content, err := ines.Read(filepath.Join(dir, manifestFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var m manifest
+			if err := json.Unmarshal(content, &m); err != nil {
+				t.Fatal(err)
+			}
+
+			var withHeader, withoutHeader []string
+
+			for _, section := range m.Sections {
+				flag := []string{joinFlagOf[section.File], filepath.Join(dir, section.File)}
+				withHeader = append(withHeader, flag...)
+
+				if section.File != "header.bin" {
+					withoutHeader = append(withoutHeader, flag...)
+				}
+			}
+
+			joins := [][]string{withHeader}
+			if tt2.withoutFlags != nil {
+				joins = append(joins, append(withoutHeader, tt2.withoutFlags...))
+			}
+
+			for _, args := range joins {
+				out := filepath.Join(dir, "joined.nes")
+				if code := run(append([]string{"join", "-o", out}, args...), &stdout, &stderr); code != 0 {
+					t.Fatalf("join %q = %v, stderr:\n%v", args, code, stderr.String())
+				}
+
+				joined, err := ines.Read(out)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(joined, tt2.b) {
+					t.Errorf("join %q did not give back the split file", args)
+				}
+			}
+		})
+	}
+}
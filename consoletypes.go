@@ -15,7 +15,7 @@ func getExtendedConsoleType(consoleTypeByte uint8) string {
 	case 3:
 		return "Regular Famiclone, but with CPU that supports Decimal Mode (e.g. Bit Corporation Creator)"
 	case 4:
-		return "V.R. Technology VT01 with monochrome palette"
+		return "Regular NES/Famicom with EPSM module or plug-through cartridge"
 	case 5:
 		return "V.R. Technology VT01 with red/cyan STN palette"
 	case 6:
@@ -30,6 +30,8 @@ func getExtendedConsoleType(consoleTypeByte uint8) string {
 		return "V.R. Technology VT369"
 	case 11:
 		return "UMC UM6578"
+	case 12:
+		return "Famicom Network System"
 	default:
 		return unknownOrUndefined
 	}
@@ -0,0 +1,201 @@
+package ines
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWrongFormat is returned when converting a Rom which is not in the expected format.
+var ErrWrongFormat = errors.New("rom is not in the expected format")
+
+// Loss is a field which could not be represented after a conversion to iNES 1.0.
+type Loss struct {
+	Field  string      // name of the Rom field
+	Value  interface{} // value before the conversion
+	Reason string
+}
+
+func (l Loss) String() string {
+	return fmt.Sprintf("%v %v: %v", l.Field, l.Value, l.Reason)
+}
+
+// ToNES2 converts an iNES 1.0, iNES 0.7 or archaic iNES rom to NES 2.0.
+// Sizes which iNES 1.0 leaves implicit are made explicit, from the mapper registry: the PRG-RAM is declared
+// as PRG-NVRAM when there is a battery, or as PRG-RAM for mappers which commonly have it, and the CHR-RAM
+// is declared when there is no CHR-ROM. The PlayChoice-10 data becomes the Miscellaneous ROM.
+// The header bytes 7-15 are rebuilt from scratch, so that garbage in them doesn't leak into NES 2.0.
+// nolint: gomnd
+func ToNES2(rom Rom) (Rom, error) {
+	if rom.HeaderType == FormatNES2 {
+		return Rom{}, fmt.Errorf("%w: want iNES 1.0, have %v", ErrWrongFormat, rom.HeaderType)
+	}
+
+	converted := rom
+	converted.Header = cleanHeader(rom.Header, FormatArchaicINES)
+	converted.HeaderType = FormatNES2
+	converted.SubMapper = 0
+	converted.CPUPPUTiming = CPUPPUTiming(rom.TVSystem)
+
+	info, _ := LookupMapper(rom.Mapper, 0)
+
+	prgram := info.PRGRAM
+	if prgram == 0 && len(rom.ProgramRAM) != 0 {
+		prgram = 8192
+	}
+
+	converted.ProgramRAM, converted.ProgramNVRam = make([]byte, prgram), []byte{}
+	if rom.HasBattery {
+		converted.ProgramRAM, converted.ProgramNVRam = []byte{}, make([]byte, prgram)
+	}
+
+	converted.CharacterRAM = make([]byte, len(rom.CharacterRAM))
+	if len(rom.CharacterRAM) != 0 && info.CHRRAM != 0 {
+		converted.CharacterRAM = make([]byte, info.CHRRAM)
+	}
+	converted.CharacterNVRam = []byte{}
+
+	if len(rom.PlayChoiceInstRom) != 0 {
+		converted.MiscRom = playChoiceMiscRom(rom)
+		converted.PlayChoiceInstRom, converted.PlayChoicePROMData, converted.PlayChoicePROMCounterOut = nil, nil, nil
+	}
+
+	return reencode(converted)
+}
+
+// ToINES converts a NES 2.0 rom to iNES 1.0, for emulators which don't support NES 2.0.
+// It returns the fields which iNES 1.0 cannot represent. Their value is replaced with the closest one
+// iNES 1.0 knows about, or dropped.
+// nolint: gomnd, cyclop, funlen
+func ToINES(rom Rom) (Rom, []Loss, error) {
+	if rom.HeaderType != FormatNES2 {
+		return Rom{}, nil, fmt.Errorf("%w: want iNES 2.0, have %v", ErrWrongFormat, rom.HeaderType)
+	}
+
+	var losses []Loss
+
+	lose := func(field string, value interface{}, reason string) {
+		losses = append(losses, Loss{Field: field, Value: value, Reason: reason})
+	}
+
+	converted := rom
+	converted.HeaderType = FormatINES
+
+	if rom.Mapper > 0xFF {
+		lose("Mapper", rom.Mapper, "iNES 1.0 only has 8 bits for the mapper, kept the lower 8 bits")
+		converted.Mapper = rom.Mapper & 0xFF
+	}
+
+	if rom.SubMapper > 0 {
+		lose("SubMapper", rom.SubMapper, "iNES 1.0 has no submapper")
+	}
+
+	converted.SubMapper = SubMapperUnspecified
+
+	switch rom.ConsoleType {
+	case ConsoleTypeNES, ConsoleTypeVs, ConsoleTypePlayChoice:
+	default:
+		lose("ConsoleType", rom.ConsoleType, "iNES 1.0 has no extended console type, used the regular NES")
+		converted.ConsoleType = ConsoleTypeNES
+	}
+
+	switch rom.TVSystem {
+	case TVSystemNTSC, TVSystemPAL:
+	case TVSystemDendy:
+		lose("TVSystem", rom.TVSystem, "iNES 1.0 only knows about NTSC and PAL, used PAL")
+		converted.TVSystem = TVSystemPAL
+	default:
+		lose("TVSystem", rom.TVSystem, "iNES 1.0 only knows about NTSC and PAL, used NTSC")
+		converted.TVSystem = TVSystemNTSC
+	}
+
+	converted.CPUPPUTiming = CPUPPUTimingUnknown
+
+	if rom.VsSystemPPU != VsPPUTypeUnknown && rom.ConsoleType == ConsoleTypeVs {
+		lose("VsSystemPPU", rom.VsSystemPPU, "iNES 1.0 has no Vs. System PPU type")
+	}
+
+	if rom.VsSystemType != VsSystemTypeUnknown && rom.ConsoleType == ConsoleTypeVs {
+		lose("VsSystemType", rom.VsSystemType, "iNES 1.0 has no Vs. System hardware type")
+	}
+
+	converted.VsSystemPPU, converted.VsSystemType = VsPPUTypeUnknown, VsSystemTypeUnknown
+
+	if rom.ExpansionDevice != ExpansionDeviceUnspecified {
+		lose("ExpansionDevice", rom.ExpansionDevice, "iNES 1.0 has no default expansion device")
+		converted.ExpansionDevice = ExpansionDeviceUnspecified
+	}
+
+	// iNES 1.0 assumes 8 KiB of PRG-RAM, battery-backed when there is a battery
+	prgram := len(rom.ProgramRAM) + len(rom.ProgramNVRam)
+	if prgram != 0 && prgram != 8192 {
+		lose("ProgramRAM", prgram, "iNES 1.0 assumes 8 KiB of PRG-RAM")
+	}
+
+	if len(rom.ProgramRAM) != 0 && len(rom.ProgramNVRam) != 0 {
+		lose("ProgramRAM", len(rom.ProgramRAM), "iNES 1.0 cannot have both PRG-RAM and PRG-NVRAM")
+	}
+
+	// iNES 1.0 assumes 8 KiB of CHR-RAM if and only if there is no CHR-ROM
+	switch {
+	case len(rom.CharacterRom) == 0 && len(rom.CharacterRAM) != 8192:
+		lose("CharacterRAM", len(rom.CharacterRAM), "iNES 1.0 assumes 8 KiB of CHR-RAM without CHR-ROM")
+	case len(rom.CharacterRom) != 0 && len(rom.CharacterRAM) != 0:
+		lose("CharacterRAM", len(rom.CharacterRAM), "iNES 1.0 cannot have both CHR-ROM and CHR-RAM")
+	}
+
+	if len(rom.CharacterNVRam) != 0 {
+		lose("CharacterNVRam", len(rom.CharacterNVRam), "iNES 1.0 has no CHR-NVRAM")
+	}
+
+	converted.ProgramRAM, converted.ProgramNVRam = []byte{}, []byte{}
+	converted.CharacterRAM, converted.CharacterNVRam = []byte{}, []byte{}
+	converted.MiscRom = []byte{}
+
+	if len(rom.MiscRom) != 0 {
+		if rom.ConsoleType == ConsoleTypePlayChoice && len(rom.MiscRom) >= 8192 {
+			// 8 KiB INST-ROM, followed by the 16 bytes of PROM Data and the 16 bytes of PROM CounterOut
+			rest := rom.MiscRom[8192:]
+			converted.PlayChoiceInstRom = rom.MiscRom[:8192]
+			converted.PlayChoicePROMData, rest = splitAt(rest, 16)
+			converted.PlayChoicePROMCounterOut, rest = splitAt(rest, 16)
+
+			if len(rest) != 0 {
+				lose("MiscRom", len(rest), "iNES 1.0 has no room after the PlayChoice-10 data, dropped it")
+			}
+		} else {
+			lose("MiscRom", len(rom.MiscRom), "iNES 1.0 has no Miscellaneous ROM Area, dropped it")
+		}
+	}
+
+	// Clear the NES 2.0 bytes, which would be garbage for iNES 1.0 readers
+	converted.Header = cleanHeader(rom.Header, FormatINES07)
+
+	converted, err := reencode(converted)
+
+	return converted, losses, err
+}
+
+// playChoiceMiscRom returns the PlayChoice-10 data of rom laid out as the NES 2.0 Miscellaneous ROM.
+func playChoiceMiscRom(rom Rom) []byte {
+	return append(append(append([]byte{}, rom.PlayChoiceInstRom...),
+		rom.PlayChoicePROMData...), rom.PlayChoicePROMCounterOut...)
+}
+
+// splitAt splits b after at most n bytes.
+func splitAt(b []byte, n int) ([]byte, []byte) {
+	if n > len(b) {
+		n = len(b)
+	}
+
+	return b[:n], b[n:]
+}
+
+// reencode encodes rom and decodes it again, so that all its fields agree with the new header.
+func reencode(rom Rom) (Rom, error) {
+	b, err := Encode(rom)
+	if err != nil {
+		return Rom{}, err
+	}
+
+	return Decode(b)
+}
@@ -0,0 +1,114 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestToNES2(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		b            []byte
+		wantPRGRAM   int
+		wantPRGNVRAM int
+		wantCHRRAM   int
+	}{
+		{
+			name: "nrom with chr-rom",
+			b:    rawRom([]byte{78, 69, 83, 26, 2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192),
+		},
+		{
+			name:       "mmc1 with chr-ram and junk in bytes 8-15",
+			b:          rawRom([]byte{78, 69, 83, 26, 2, 0, 0x10, 0, 0, 0, 0, 0, 'J', 'U', 'N', 'K'}, 32768),
+			wantPRGRAM: 8192,
+			wantCHRRAM: 8192,
+		},
+		{
+			name:         "mmc3 with battery",
+			b:            rawRom([]byte{78, 69, 83, 26, 2, 1, 0x42, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192),
+			wantPRGNVRAM: 8192,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			converted, err := ToNES2(rom)
+			if err != nil {
+				t.Fatalf("ToNES2() error = %v", err)
+			}
+
+			if !converted.Header.IsNES2() || converted.Mapper != rom.Mapper {
+				t.Errorf("ToNES2() header = % x", converted.Header)
+			}
+
+			if len(converted.ProgramRAM) != tt2.wantPRGRAM || len(converted.ProgramNVRam) != tt2.wantPRGNVRAM {
+				t.Errorf("ToNES2() PRG-RAM, PRG-NVRAM = %v, %v, want %v, %v",
+					len(converted.ProgramRAM), len(converted.ProgramNVRam), tt2.wantPRGRAM, tt2.wantPRGNVRAM)
+			}
+
+			if len(converted.CharacterRAM) != tt2.wantCHRRAM {
+				t.Errorf("ToNES2() CHR-RAM = %v, want %v", len(converted.CharacterRAM), tt2.wantCHRRAM)
+			}
+
+			back, losses, err := ToINES(converted)
+			if err != nil {
+				t.Fatalf("ToINES() error = %v", err)
+			}
+
+			if len(losses) != 0 {
+				t.Errorf("ToINES() losses = %v, want none", losses)
+			}
+
+			if back.Mapper != rom.Mapper || !bytes.Equal(back.Headerless, rom.Headerless) {
+				t.Errorf("ToINES() did not give back the original rom")
+			}
+		})
+	}
+}
+
+func TestToINES_losses(t *testing.T) {
+	t.Parallel()
+
+	// Mapper 0x101, submapper 2, Dendy, CHR-NVRAM and a Zapper
+	b := rawRom([]byte{78, 69, 83, 26, 2, 1, 0x10, 0x08, 0x21, 0, 0, 0x70, 3, 0, 0, 8}, 32768, 8192)
+
+	rom, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, losses, err := ToINES(rom)
+	if err != nil {
+		t.Fatalf("ToINES() error = %v", err)
+	}
+
+	var fields []string
+	for _, loss := range losses {
+		fields = append(fields, loss.Field)
+	}
+
+	want := []string{"Mapper", "SubMapper", "TVSystem", "ExpansionDevice", "CharacterNVRam"}
+	if len(fields) != len(want) {
+		t.Fatalf("ToINES() losses = %v, want %v", fields, want)
+	}
+
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("ToINES() losses = %v, want %v", fields, want)
+		}
+	}
+
+	if converted.Mapper != 1 || converted.TVSystem != TVSystemPAL || converted.Header.IsNES2() {
+		t.Errorf("ToINES() mapper, TV system, header = %v, %v, % x", converted.Mapper, converted.TVSystem, converted.Header)
+	}
+}
@@ -0,0 +1,152 @@
+package ines
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DAT is a Logiqx XML datafile, the format of the No-Intro and ClrMamePro databases.
+// The hashes of NES DATs are those of the headerless data.
+// http://www.logiqx.com/DatFAQs/
+type DAT struct {
+	XMLName     xml.Name  `xml:"datafile"`
+	Name        string    `xml:"header>name"`
+	Description string    `xml:"header>description"`
+	Version     string    `xml:"header>version,omitempty"`
+	Games       []DATGame `xml:"game"`
+}
+
+// DATGame is a game of a DAT.
+type DATGame struct {
+	Name        string   `xml:"name,attr"`
+	Description string   `xml:"description"`
+	ROMs        []DATRom `xml:"rom"`
+}
+
+// DATRom is a file of a DAT game. The hashes are in hex.
+// An empty Status means a good dump, the others are "baddump", "nodump" and "verified".
+type DATRom struct {
+	Name   string `xml:"name,attr"`
+	Size   int    `xml:"size,attr"`
+	CRC    string `xml:"crc,attr"`
+	MD5    string `xml:"md5,attr,omitempty"`
+	SHA1   string `xml:"sha1,attr,omitempty"`
+	Status string `xml:"status,attr,omitempty"`
+}
+
+// NewDATRom returns the DAT file named name with the hashes of digest, in upper case hex as No-Intro writes them.
+func NewDATRom(name string, digest Digest) DATRom {
+	return DATRom{
+		Name: name,
+		Size: digest.Size,
+		CRC:  fmt.Sprintf("%08X", digest.CRC32),
+		MD5:  fmt.Sprintf("%X", digest.MD5),
+		SHA1: fmt.Sprintf("%X", digest.SHA1),
+	}
+}
+
+const datDoctype = `<!DOCTYPE datafile PUBLIC "-//Logiqx//DTD ROM Management Datafile//EN" ` +
+	`"http://www.logiqx.com/Dats/datafile.dtd">`
+
+// LoadDAT reads the Logiqx XML DAT file at path.
+func LoadDAT(path string) (*DAT, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the DAT %v - Error: %w", path, err)
+	}
+	defer f.Close()
+
+	return ParseDAT(f)
+}
+
+// ParseDAT parses a Logiqx XML DAT.
+func ParseDAT(r io.Reader) (*DAT, error) {
+	var dat DAT
+
+	if err := xml.NewDecoder(r).Decode(&dat); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadDatabase, err)
+	}
+
+	return &dat, nil
+}
+
+// Match finds the game and the file of rom by the SHA-1 of its headerless data,
+// or by its CRC32 if the DAT has no SHA-1 for the file.
+func (d *DAT) Match(rom Rom) (DATGame, DATRom, bool) {
+	digest := Hash(rom).Headerless
+	crc, sha1 := fmt.Sprintf("%08x", digest.CRC32), fmt.Sprintf("%x", digest.SHA1)
+
+	for _, game := range d.Games {
+		for _, file := range game.ROMs {
+			if file.Size != digest.Size {
+				continue
+			}
+
+			if file.SHA1 != "" && strings.EqualFold(file.SHA1, sha1) ||
+				file.SHA1 == "" && strings.EqualFold(file.CRC, crc) {
+				return game, file, true
+			}
+		}
+	}
+
+	return DATGame{}, DATRom{}, false
+}
+
+// Write writes d as a Logiqx XML DAT.
+func (d *DAT) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header+datDoctype+"\n"); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+
+	if err := enc.Encode(d); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// GenerateDAT builds a DAT named name out of the .nes files found under dir, with one game per file.
+// The files are decoded with Decode, and hashed without their header.
+func GenerateDAT(name string, dir string) (*DAT, error) {
+	dat := &DAT{Name: name, Description: name}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".nes") {
+			return err
+		}
+
+		content, err := Read(path)
+		if err != nil {
+			return err
+		}
+
+		rom, err := Decode(content)
+		if err != nil {
+			return fmt.Errorf("failed to decode %v: %w", path, err)
+		}
+
+		game := strings.TrimSuffix(info.Name(), filepath.Ext(path))
+
+		dat.Games = append(dat.Games, DATGame{
+			Name:        game,
+			Description: game,
+			ROMs:        []DATRom{NewDATRom(info.Name(), Hash(rom).Headerless)},
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dat, nil
+}
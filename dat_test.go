@@ -0,0 +1,103 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDAT_Match(t *testing.T) {
+	t.Parallel()
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rom, err := Decode(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		dat        string
+		wantGame   string
+		wantStatus string
+		wantOK     bool
+	}{
+		{
+			name: "by sha1",
+			dat: `<datafile><header><name>NES</name></header>
+				<game name="The Wit (Demo)"><rom name="The Wit (Demo).nes" size="40960" crc="00000000"
+				sha1="b15b26b07cf13475cae3d61e463e93f39632250f" status="verified"/></game></datafile>`,
+			wantGame:   "The Wit (Demo)",
+			wantStatus: "verified",
+			wantOK:     true,
+		},
+		{
+			name: "by crc32 without sha1",
+			dat: `<datafile><game name="The Wit (Demo)"><rom name="a.nes" size="40960" crc="730E70AC"/></game>
+				</datafile>`,
+			wantGame: "The Wit (Demo)",
+			wantOK:   true,
+		},
+		{
+			name: "wrong sha1",
+			dat: `<datafile><game name="The Wit (Demo)"><rom name="a.nes" size="40960" crc="730E70AC"
+				sha1="0000000000000000000000000000000000000000"/></game></datafile>`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			dat, err := ParseDAT(strings.NewReader(tt2.dat))
+			if err != nil {
+				t.Fatalf("ParseDAT() error = %v", err)
+			}
+
+			game, file, ok := dat.Match(rom)
+			if ok != tt2.wantOK || game.Name != tt2.wantGame || file.Status != tt2.wantStatus {
+				t.Errorf("Match() = %q, %q, %v, want %q, %q, %v",
+					game.Name, file.Status, ok, tt2.wantGame, tt2.wantStatus, tt2.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateDAT(t *testing.T) {
+	t.Parallel()
+
+	dat, err := GenerateDAT("testdata", "testdata")
+	if err != nil {
+		t.Fatalf("GenerateDAT() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := dat.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	parsed, err := ParseDAT(&buf)
+	if err != nil {
+		t.Fatalf("ParseDAT() error = %v", err)
+	}
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rom, err := Decode(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game, file, ok := parsed.Match(rom)
+	if !ok || game.Name != "thewit-demo" || file.Name != "thewit-demo.nes" || file.CRC != "730E70AC" {
+		t.Errorf("Match() = %+v, %+v, %v", game, file, ok)
+	}
+}
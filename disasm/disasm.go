@@ -0,0 +1,181 @@
+// Package disasm disassembles 6502 machine code, as found in the PRG-ROM and the trainer of NES files,
+// into ca65 syntax.
+package disasm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TrainerOrigin is the CPU address the trainer is loaded at.
+const TrainerOrigin = 0x7000
+
+// Origin returns the CPU address PRG-ROM of the given size is mapped at, without bank switching:
+// 32 KiB fills $8000-$FFFF, and smaller sizes are mirrored up to the vectors, like 16 KiB NROM at $C000.
+// Larger PRG-ROM is bank switched, and Origin returns $8000.
+func Origin(prgSize int) uint16 {
+	if prgSize <= 0 || prgSize > 0x8000 {
+		return 0x8000
+	}
+
+	return uint16(0x10000 - prgSize)
+}
+
+// Options tells how to decode the machine code.
+type Options struct {
+	Origin     uint16 // CPU address of the first byte
+	Unofficial bool   // decode the unofficial opcodes too, rather than as data
+}
+
+// Instruction is a decoded instruction. An opcode which is not decoded, or an instruction cut short
+// by the end of the code, gives a data byte with an empty Mnemonic.
+type Instruction struct {
+	Address  uint16
+	Bytes    []byte
+	Mnemonic string
+	Mode     Mode
+	Operand  uint16 // the target address for relative branches
+}
+
+// Decode decodes b from the start to the end, one instruction after the other.
+func Decode(b []byte, opts Options) []Instruction {
+	var instructions []Instruction
+
+	for pc := 0; pc < len(b); {
+		address := opts.Origin + uint16(pc)
+		opcode := Opcodes[b[pc]]
+
+		if opcode.Mnemonic == "" || !opcode.Official && !opts.Unofficial || pc+opcode.Mode.Size() > len(b) {
+			instructions = append(instructions, Instruction{Address: address, Bytes: b[pc : pc+1]})
+			pc++
+
+			continue
+		}
+
+		in := Instruction{
+			Address:  address,
+			Bytes:    b[pc : pc+opcode.Mode.Size()],
+			Mnemonic: opcode.Mnemonic,
+			Mode:     opcode.Mode,
+		}
+
+		switch opcode.Mode.Size() {
+		case 2: // nolint: gomnd
+			in.Operand = uint16(b[pc+1])
+		case 3: // nolint: gomnd
+			in.Operand = uint16(b[pc+1]) | uint16(b[pc+2])<<8
+		}
+
+		if opcode.Mode == Relative {
+			in.Operand = address + 2 + uint16(int8(b[pc+1]))
+		}
+
+		instructions = append(instructions, in)
+		pc += opcode.Mode.Size()
+	}
+
+	return instructions
+}
+
+// String returns the instruction in ca65 syntax, with the operand addresses in hex.
+func (in Instruction) String() string {
+	return in.format(func(address uint16) string { return fmt.Sprintf("$%04X", address) })
+}
+
+// format returns the instruction in ca65 syntax, with name giving the operand addresses.
+func (in Instruction) format(name func(uint16) string) string {
+	if in.Mnemonic == "" {
+		return fmt.Sprintf(".byte   $%02X", in.Bytes[0])
+	}
+
+	var operand string
+
+	switch in.Mode {
+	case Implied:
+		return in.Mnemonic
+	case Accumulator:
+		operand = "a"
+	case Immediate:
+		operand = fmt.Sprintf("#$%02X", in.Operand)
+	case ZeroPage:
+		operand = fmt.Sprintf("$%02X", in.Operand)
+	case ZeroPageX:
+		operand = fmt.Sprintf("$%02X,x", in.Operand)
+	case ZeroPageY:
+		operand = fmt.Sprintf("$%02X,y", in.Operand)
+	case Absolute, AbsoluteX, AbsoluteY:
+		operand = name(in.Operand)
+		if in.Operand < 0x100 {
+			operand = "a:" + operand // or ca65 would assemble it in zero page mode
+		}
+
+		operand += map[Mode]string{AbsoluteX: ",x", AbsoluteY: ",y"}[in.Mode]
+	case Indirect:
+		operand = "(" + name(in.Operand) + ")"
+	case IndirectX:
+		operand = fmt.Sprintf("($%02X,x)", in.Operand)
+	case IndirectY:
+		operand = fmt.Sprintf("($%02X),y", in.Operand)
+	case Relative:
+		operand = name(in.Operand)
+	}
+
+	return fmt.Sprintf("%-8v%v", in.Mnemonic, operand)
+}
+
+// Listing writes the disassembly of b as a ca65 source, with the address and the bytes of each
+// instruction in a comment. The targets of branches, jumps and subroutine calls inside b get labels.
+func Listing(w io.Writer, b []byte, opts Options) error {
+	instructions := Decode(b, opts)
+
+	labels := make(map[uint16]bool)
+
+	for _, in := range instructions {
+		if in.Mode == Relative || in.Mnemonic == "jmp" && in.Mode == Absolute || in.Mnemonic == "jsr" {
+			labels[in.Operand] = true
+		}
+	}
+
+	// Only the instructions can take a label
+	named := make(map[uint16]bool)
+
+	for _, in := range instructions {
+		if labels[in.Address] {
+			named[in.Address] = true
+		}
+	}
+
+	name := func(address uint16) string {
+		if named[address] {
+			return fmt.Sprintf("L%04X", address)
+		}
+
+		return fmt.Sprintf("$%04X", address)
+	}
+
+	var sb strings.Builder
+
+	if opts.Unofficial {
+		sb.WriteString(".setcpu \"6502X\"\n")
+	}
+
+	fmt.Fprintf(&sb, ".org    $%04X\n\n", opts.Origin)
+
+	for _, in := range instructions {
+		if named[in.Address] {
+			fmt.Fprintf(&sb, "L%04X:\n", in.Address)
+		}
+
+		hex := make([]string, len(in.Bytes))
+		for i, v := range in.Bytes {
+			hex[i] = fmt.Sprintf("%02X", v)
+		}
+
+		fmt.Fprintf(&sb, "        %-24v; %04X  %v\n", in.format(name), in.Address, strings.Join(hex, " "))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,137 @@
+package disasm // nolint: testpackage
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOpcodes(t *testing.T) {
+	t.Parallel()
+
+	var official, unofficial int
+
+	for code, opcode := range Opcodes {
+		switch {
+		case opcode.Mnemonic == "":
+			t.Errorf("opcode %02X is not defined", code)
+		case opcode.Official:
+			official++
+		default:
+			unofficial++
+		}
+	}
+
+	if official != 151 || unofficial != 105 {
+		t.Errorf("have %v official and %v unofficial opcodes, want 151 and 105", official, unofficial)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size int
+		want uint16
+	}{
+		{size: 8192, want: 0xE000},
+		{size: 16384, want: 0xC000},
+		{size: 32768, want: 0x8000},
+		{size: 131072, want: 0x8000},
+	}
+
+	for _, tt := range tests {
+		if got := Origin(tt.size); got != tt.want {
+			t.Errorf("Origin(%v) = $%04X, want $%04X", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	code := []byte{
+		0x78,       // sei
+		0xA9, 0x10, // lda #$10
+		0x8D, 0x00, 0x20, // sta $2000
+		0xBD, 0x10, 0x00, // lda a:$0010,x
+		0xB1, 0x20, // lda ($20),y
+		0x0A,             // asl a
+		0x6C, 0xFC, 0xFF, // jmp ($FFFC)
+		0xD0, 0xFE, // bne to itself
+		0xA7, 0x00, // lax $00, unofficial
+		0x4C, // jmp cut short
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "official",
+			opts: Options{Origin: 0xC000},
+			want: []string{
+				"sei", "lda     #$10", "sta     $2000", "lda     a:$0010,x", "lda     ($20),y", "asl     a",
+				"jmp     ($FFFC)", "bne     $C00F", ".byte   $A7", "brk", ".byte   $4C",
+			},
+		},
+		{
+			name: "unofficial",
+			opts: Options{Origin: 0xC000, Unofficial: true},
+			want: []string{
+				"sei", "lda     #$10", "sta     $2000", "lda     a:$0010,x", "lda     ($20),y", "asl     a",
+				"jmp     ($FFFC)", "bne     $C00F", "lax     $00", ".byte   $4C",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			instructions := Decode(code, tt2.opts)
+			if len(instructions) != len(tt2.want) {
+				t.Fatalf("Decode() = %v instructions, want %v", len(instructions), len(tt2.want))
+			}
+
+			for i, in := range instructions {
+				if in.String() != tt2.want[i] {
+					t.Errorf("Decode()[%v] = %q, want %q", i, in, tt2.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListing(t *testing.T) {
+	t.Parallel()
+
+	code := []byte{
+		0x20, 0x06, 0x80, // jsr sub
+		0x4C, 0x00, 0x80, // jmp to the start
+		0xCA,       // sub: dex
+		0xD0, 0xFD, // bne sub
+		0x60, // rts
+	}
+
+	want := `.org    $8000
+
+L8000:
+        jsr     L8006           ; 8000  20 06 80
+        jmp     L8000           ; 8003  4C 00 80
+L8006:
+        dex                     ; 8006  CA
+        bne     L8006           ; 8007  D0 FD
+        rts                     ; 8009  60
+`
+
+	var buf bytes.Buffer
+	if err := Listing(&buf, code, Options{Origin: 0x8000}); err != nil {
+		t.Fatalf("Listing() error = %v", err)
+	}
+
+	if buf.String() != want {
+		t.Errorf("Listing() =\n%v\nwant\n%v", buf.String(), want)
+	}
+}
@@ -0,0 +1,166 @@
+package disasm
+
+// Mode is the addressing mode of an instruction, which gives the size and the syntax of its operand.
+type Mode int
+
+const (
+	Implied     Mode = iota // rts
+	Accumulator             // asl a
+	Immediate               // lda #$10
+	ZeroPage                // lda $10
+	ZeroPageX               // lda $10,x
+	ZeroPageY               // ldx $10,y
+	Absolute                // lda $1234
+	AbsoluteX               // lda $1234,x
+	AbsoluteY               // lda $1234,y
+	Indirect                // jmp ($1234)
+	IndirectX               // lda ($10,x)
+	IndirectY               // lda ($10),y
+	Relative                // bne $C010
+)
+
+// Size returns the size in bytes of an instruction in the mode, opcode included.
+func (m Mode) Size() int {
+	switch m {
+	case Implied, Accumulator:
+		return 1
+	case Absolute, AbsoluteX, AbsoluteY, Indirect:
+		return 3 // nolint: gomnd
+	default:
+		return 2 // nolint: gomnd
+	}
+}
+
+// Opcode describes what an opcode byte decodes to.
+type Opcode struct {
+	Mnemonic string
+	Mode     Mode
+	Official bool
+}
+
+// Opcodes holds the 256 opcodes of the NMOS 6502, with the ca65 "6502X" names of the unofficial ones.
+// https://www.nesdev.org/wiki/CPU_unofficial_opcodes
+var Opcodes = func() [256]Opcode { // nolint: gochecknoglobals
+	var table [256]Opcode
+
+	set := func(official bool, mnemonic string, modes map[Mode][]byte) {
+		for mode, codes := range modes {
+			for _, code := range codes {
+				table[code] = Opcode{Mnemonic: mnemonic, Mode: mode, Official: official}
+			}
+		}
+	}
+
+	// The 8 addressing modes of the ALU instructions, in the order of their opcodes
+	alu := func(official bool, mnemonic string, imm, zp, zpx, abs, absx, absy, indx, indy byte) {
+		set(official, mnemonic, map[Mode][]byte{
+			Immediate: {imm}, ZeroPage: {zp}, ZeroPageX: {zpx}, Absolute: {abs},
+			AbsoluteX: {absx}, AbsoluteY: {absy}, IndirectX: {indx}, IndirectY: {indy},
+		})
+	}
+
+	// The 7 addressing modes of the unofficial read-modify-write instructions
+	rmw := func(mnemonic string, zp, zpx, abs, absx, absy, indx, indy byte) {
+		set(false, mnemonic, map[Mode][]byte{
+			ZeroPage: {zp}, ZeroPageX: {zpx}, Absolute: {abs},
+			AbsoluteX: {absx}, AbsoluteY: {absy}, IndirectX: {indx}, IndirectY: {indy},
+		})
+	}
+
+	// The 5 addressing modes of the shifts, INC and DEC; code 0 means there is no accumulator mode
+	shift := func(mnemonic string, acc, zp, zpx, abs, absx byte) {
+		modes := map[Mode][]byte{ZeroPage: {zp}, ZeroPageX: {zpx}, Absolute: {abs}, AbsoluteX: {absx}}
+		if acc != 0 {
+			modes[Accumulator] = []byte{acc}
+		}
+
+		set(true, mnemonic, modes)
+	}
+
+	implied := func(official bool, codes map[string]byte) {
+		for mnemonic, code := range codes {
+			table[code] = Opcode{Mnemonic: mnemonic, Mode: Implied, Official: official}
+		}
+	}
+
+	alu(true, "ora", 0x09, 0x05, 0x15, 0x0D, 0x1D, 0x19, 0x01, 0x11)
+	alu(true, "and", 0x29, 0x25, 0x35, 0x2D, 0x3D, 0x39, 0x21, 0x31)
+	alu(true, "eor", 0x49, 0x45, 0x55, 0x4D, 0x5D, 0x59, 0x41, 0x51)
+	alu(true, "adc", 0x69, 0x65, 0x75, 0x6D, 0x7D, 0x79, 0x61, 0x71)
+	alu(true, "lda", 0xA9, 0xA5, 0xB5, 0xAD, 0xBD, 0xB9, 0xA1, 0xB1)
+	alu(true, "cmp", 0xC9, 0xC5, 0xD5, 0xCD, 0xDD, 0xD9, 0xC1, 0xD1)
+	alu(true, "sbc", 0xE9, 0xE5, 0xF5, 0xED, 0xFD, 0xF9, 0xE1, 0xF1)
+	set(true, "sta", map[Mode][]byte{
+		ZeroPage: {0x85}, ZeroPageX: {0x95}, Absolute: {0x8D},
+		AbsoluteX: {0x9D}, AbsoluteY: {0x99}, IndirectX: {0x81}, IndirectY: {0x91},
+	})
+
+	shift("asl", 0x0A, 0x06, 0x16, 0x0E, 0x1E)
+	shift("rol", 0x2A, 0x26, 0x36, 0x2E, 0x3E)
+	shift("lsr", 0x4A, 0x46, 0x56, 0x4E, 0x5E)
+	shift("ror", 0x6A, 0x66, 0x76, 0x6E, 0x7E)
+	shift("dec", 0, 0xC6, 0xD6, 0xCE, 0xDE)
+	shift("inc", 0, 0xE6, 0xF6, 0xEE, 0xFE)
+
+	set(true, "ldx", map[Mode][]byte{
+		Immediate: {0xA2}, ZeroPage: {0xA6}, ZeroPageY: {0xB6}, Absolute: {0xAE}, AbsoluteY: {0xBE},
+	})
+	set(true, "ldy", map[Mode][]byte{
+		Immediate: {0xA0}, ZeroPage: {0xA4}, ZeroPageX: {0xB4}, Absolute: {0xAC}, AbsoluteX: {0xBC},
+	})
+	set(true, "stx", map[Mode][]byte{ZeroPage: {0x86}, ZeroPageY: {0x96}, Absolute: {0x8E}})
+	set(true, "sty", map[Mode][]byte{ZeroPage: {0x84}, ZeroPageX: {0x94}, Absolute: {0x8C}})
+	set(true, "cpx", map[Mode][]byte{Immediate: {0xE0}, ZeroPage: {0xE4}, Absolute: {0xEC}})
+	set(true, "cpy", map[Mode][]byte{Immediate: {0xC0}, ZeroPage: {0xC4}, Absolute: {0xCC}})
+	set(true, "bit", map[Mode][]byte{ZeroPage: {0x24}, Absolute: {0x2C}})
+	set(true, "jmp", map[Mode][]byte{Absolute: {0x4C}, Indirect: {0x6C}})
+	set(true, "jsr", map[Mode][]byte{Absolute: {0x20}})
+
+	for mnemonic, code := range map[string]byte{
+		"bpl": 0x10, "bmi": 0x30, "bvc": 0x50, "bvs": 0x70, "bcc": 0x90, "bcs": 0xB0, "bne": 0xD0, "beq": 0xF0,
+	} {
+		table[code] = Opcode{Mnemonic: mnemonic, Mode: Relative, Official: true}
+	}
+
+	implied(true, map[string]byte{
+		"brk": 0x00, "php": 0x08, "clc": 0x18, "plp": 0x28, "sec": 0x38, "rti": 0x40, "pha": 0x48, "cli": 0x58,
+		"rts": 0x60, "pla": 0x68, "sei": 0x78, "dey": 0x88, "txa": 0x8A, "tya": 0x98, "txs": 0x9A, "tay": 0xA8,
+		"tax": 0xAA, "clv": 0xB8, "tsx": 0xBA, "iny": 0xC8, "dex": 0xCA, "cld": 0xD8, "inx": 0xE8, "nop": 0xEA,
+		"sed": 0xF8,
+	})
+
+	// Unofficial opcodes
+	rmw("slo", 0x07, 0x17, 0x0F, 0x1F, 0x1B, 0x03, 0x13)
+	rmw("rla", 0x27, 0x37, 0x2F, 0x3F, 0x3B, 0x23, 0x33)
+	rmw("sre", 0x47, 0x57, 0x4F, 0x5F, 0x5B, 0x43, 0x53)
+	rmw("rra", 0x67, 0x77, 0x6F, 0x7F, 0x7B, 0x63, 0x73)
+	rmw("dcp", 0xC7, 0xD7, 0xCF, 0xDF, 0xDB, 0xC3, 0xD3)
+	rmw("isc", 0xE7, 0xF7, 0xEF, 0xFF, 0xFB, 0xE3, 0xF3)
+	set(false, "sax", map[Mode][]byte{ZeroPage: {0x87}, ZeroPageY: {0x97}, Absolute: {0x8F}, IndirectX: {0x83}})
+	set(false, "lax", map[Mode][]byte{
+		Immediate: {0xAB}, ZeroPage: {0xA7}, ZeroPageY: {0xB7}, Absolute: {0xAF},
+		AbsoluteY: {0xBF}, IndirectX: {0xA3}, IndirectY: {0xB3},
+	})
+	set(false, "anc", map[Mode][]byte{Immediate: {0x0B, 0x2B}})
+	set(false, "alr", map[Mode][]byte{Immediate: {0x4B}})
+	set(false, "arr", map[Mode][]byte{Immediate: {0x6B}})
+	set(false, "ane", map[Mode][]byte{Immediate: {0x8B}})
+	set(false, "axs", map[Mode][]byte{Immediate: {0xCB}})
+	set(false, "sbc", map[Mode][]byte{Immediate: {0xEB}})
+	set(false, "sha", map[Mode][]byte{AbsoluteY: {0x9F}, IndirectY: {0x93}})
+	set(false, "shx", map[Mode][]byte{AbsoluteY: {0x9E}})
+	set(false, "shy", map[Mode][]byte{AbsoluteX: {0x9C}})
+	set(false, "tas", map[Mode][]byte{AbsoluteY: {0x9B}})
+	set(false, "las", map[Mode][]byte{AbsoluteY: {0xBB}})
+	set(false, "nop", map[Mode][]byte{
+		Implied:   {0x1A, 0x3A, 0x5A, 0x7A, 0xDA, 0xFA},
+		Immediate: {0x80, 0x82, 0x89, 0xC2, 0xE2},
+		ZeroPage:  {0x04, 0x44, 0x64},
+		ZeroPageX: {0x14, 0x34, 0x54, 0x74, 0xD4, 0xF4},
+		Absolute:  {0x0C},
+		AbsoluteX: {0x1C, 0x3C, 0x5C, 0x7C, 0xDC, 0xFC},
+	})
+	set(false, "jam", map[Mode][]byte{Implied: {0x02, 0x12, 0x22, 0x32, 0x42, 0x52, 0x62, 0x72, 0x92, 0xB2, 0xD2, 0xF2}})
+
+	return table
+}()
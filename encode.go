@@ -0,0 +1,176 @@
+package ines
+
+import (
+	"fmt"
+)
+
+// Encode builds an iNES 1.0 or NES 2.0 file out of rom, depending on its HeaderType.
+// For archaic iNES and iNES 0.7, only the header bytes which the format defines are written.
+// The header is rebuilt from the structured fields of rom. Bits which rom doesn't model
+// are taken over from rom.Header, so that Decode followed by Encode gives back the same bytes.
+// The sections are laid out in spec order: trainer, PRG-ROM, CHR-ROM, PlayChoice-10 data or
+// Miscellaneous ROM and finally the title block.
+func Encode(rom Rom) ([]byte, error) {
+	if len(rom.Trainer) != 0 && len(rom.Trainer) != 512 {
+		return nil, fmt.Errorf("%w: trainer size %v", ErrUnencodable, len(rom.Trainer))
+	}
+
+	header, err := encodeHeader(rom)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, headerSize+len(rom.Trainer)+len(rom.ProgramRom)+len(rom.CharacterRom))
+	buf = append(buf, header[:]...)
+	buf = append(buf, rom.Trainer...)
+	buf = append(buf, rom.ProgramRom...)
+	buf = append(buf, rom.CharacterRom...)
+
+	if rom.HeaderType == FormatNES2 {
+		buf = append(buf, rom.MiscRom...)
+	} else {
+		buf = append(buf, rom.PlayChoiceInstRom...)
+		buf = append(buf, rom.PlayChoicePROMData...)
+		buf = append(buf, rom.PlayChoicePROMCounterOut...)
+	}
+
+	buf = append(buf, rom.Title...)
+
+	return buf, nil
+}
+
+// encodeHeader rebuilds the header from the structured fields of rom, on top of rom.Header.
+// The bytes which the format doesn't define are kept as they are.
+func encodeHeader(rom Rom) (Header, error) {
+	header := cleanHeader(rom.Header, rom.HeaderType)
+	copy(header[:], hexBytes("4e45531a"))
+
+	if rom.HeaderType == FormatNES2 {
+		header.SetNES2(true)
+	} else if header.IsNES2() {
+		header.SetNES2(false)
+	}
+
+	// Archaic iNES has no room for the upper nibble of the mapper
+	if rom.HeaderType == FormatArchaicINES && rom.Mapper > 0x0F {
+		return Header{}, fmt.Errorf("%w: mapper %v in %v", ErrUnencodable, rom.Mapper, rom.HeaderType)
+	}
+
+	header.SetBattery(rom.HasBattery)
+	header.SetTrainer(len(rom.Trainer) != 0)
+
+	if err := header.SetMirroring(rom.Mirroring); err != nil {
+		return Header{}, err
+	}
+
+	if err := header.SetMapper(rom.Mapper); err != nil {
+		return Header{}, err
+	}
+
+	if err := header.SetPRGROMSize(len(rom.ProgramRom)); err != nil {
+		return Header{}, err
+	}
+
+	if err := header.SetCHRROMSize(len(rom.CharacterRom)); err != nil {
+		return Header{}, err
+	}
+
+	if err := header.SetConsoleType(rom.ConsoleType); err != nil {
+		return Header{}, err
+	}
+
+	if header.IsNES2() {
+		if err := encodeHeader2(&header, rom); err != nil {
+			return Header{}, err
+		}
+
+		return header, nil
+	}
+
+	if err := encodeHeader1(&header, rom); err != nil {
+		return Header{}, err
+	}
+
+	return mergeHeader(rom.Header, header, rom.HeaderType), nil
+}
+
+// encodeHeader1 fills the iNES 1.0 specific fields.
+// nolint: gomnd
+func encodeHeader1(header *Header, rom Rom) error {
+	// The PlayChoice-10 data is there whenever bit 1 is set, even for a Vs. System
+	if len(rom.PlayChoiceInstRom) != 0 {
+		header[7] = setBit(header[7], 1, true)
+	}
+
+	return header.SetTVSystem(rom.TVSystem)
+}
+
+// encodeHeader2 fills the NES 2.0 specific fields.
+// nolint: gomnd, cyclop
+func encodeHeader2(header *Header, rom Rom) error {
+	// NES 2.0 has no way to leave the submapper unspecified, 0 is the default behavior of the mapper
+	if rom.SubMapper != SubMapperUnspecified {
+		if err := header.SetSubMapper(rom.SubMapper); err != nil {
+			return err
+		}
+	}
+
+	if err := setRAMShift(header.SetPRGRAMShift, len(rom.ProgramRAM)); err != nil {
+		return err
+	}
+
+	// PRG-NVRAM is only decoded when there is a battery
+	if rom.HasBattery || len(rom.ProgramNVRam) != 0 {
+		if err := setRAMShift(header.SetPRGNVRAMShift, len(rom.ProgramNVRam)); err != nil {
+			return err
+		}
+	}
+
+	if err := setRAMShift(header.SetCHRRAMShift, len(rom.CharacterRAM)); err != nil {
+		return err
+	}
+
+	if err := setRAMShift(header.SetCHRNVRAMShift, len(rom.CharacterNVRam)); err != nil {
+		return err
+	}
+
+	// iNES 1.0 only knows about the TV system, which shares its values with the timing
+	timing := rom.CPUPPUTiming
+	if timing == CPUPPUTimingUnknown {
+		timing = CPUPPUTiming(rom.TVSystem)
+	}
+
+	if err := header.SetCPUPPUTiming(timing); err != nil {
+		return err
+	}
+
+	if rom.VsSystemPPU != VsPPUTypeUnknown {
+		if err := header.SetVsPPUType(rom.VsSystemPPU); err != nil {
+			return err
+		}
+	}
+
+	if rom.VsSystemType != VsSystemTypeUnknown {
+		if err := header.SetVsSystemType(rom.VsSystemType); err != nil {
+			return err
+		}
+	}
+
+	if len(rom.MiscRom) != 0 && header.MiscROMCount() == 0 {
+		if err := header.SetMiscROMCount(1); err != nil {
+			return err
+		}
+	}
+
+	return header.SetExpansionDevice(rom.ExpansionDevice)
+}
+
+// setRAMShift sets a NES 2.0 shift count with the given setter, out of a RAM size in bytes.
+func setRAMShift(set func(shift int) error, size int) error {
+	shift, err := shiftCount(size)
+	if err != nil {
+		return err
+	}
+
+	return set(int(shift))
+}
@@ -0,0 +1,182 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// nolint: gomnd
+func rawRom(header []byte, sizes ...int) []byte {
+	b := append([]byte{}, header...)
+
+	for i, size := range sizes {
+		b = append(b, bytes.Repeat([]byte{byte(i + 1)}, size)...)
+	}
+
+	return b
+}
+
+func TestEncode_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{
+			name: "ines 1.0 demo",
+			b:    demo,
+		},
+		{
+			name: "ines 1.0 with trainer, battery, four-screen and title",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 0, 0x4F, 0x10, 0, 1, 0, 0, 0, 0, 0, 0}, 512, 16384, 128),
+		},
+		{
+			name: "ines 1.0 playchoice",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0x02, 0, 0, 0, 0, 0, 0, 0, 0}, 16384, 8192, 8192, 16, 16),
+		},
+		{
+			name: "ines 2.0 with submapper, ram sizes and expansion device",
+			b:    rawRom([]byte{78, 69, 83, 26, 2, 1, 0x13, 0x08, 0x51, 0, 0x70, 0x07, 1, 0, 0, 1}, 32768, 8192),
+		},
+		{
+			name: "ines 2.0 exponent-multiplier prg-rom size",
+			b:    rawRom([]byte{78, 69, 83, 26, 0x39, 0, 0, 0x08, 0, 0x0F, 0, 0x07, 0, 0, 0, 0}, 49152),
+		},
+		{
+			name: "ines 2.0 with misc rom",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 0, 0, 0x08, 0, 0, 0, 0x07, 0, 0, 1, 0}, 16384, 100),
+		},
+		{
+			name: "ines 2.0 with title",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 1, 0x08, 0, 0, 0, 0, 2, 0, 0, 0}, 16384, 8192, 127),
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			got, err := Encode(rom)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if !bytes.Equal(got, tt2.b) {
+				t.Errorf("Encode() header = % x, want % x", got[:16], tt2.b[:16])
+				t.Errorf("Encode() size = %v, want %v", len(got), len(tt2.b))
+			}
+		})
+	}
+}
+
+func TestEncode_errors(t *testing.T) {
+	t.Parallel()
+
+	rom, err := Decode(rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 16384, 8192))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(rom *Rom)
+	}{
+		{
+			name:   "prg-rom size not a multiple of 16 KiB in ines 1.0",
+			modify: func(rom *Rom) { rom.ProgramRom = make([]byte, 100) },
+		},
+		{
+			name:   "mapper above 255 in ines 1.0",
+			modify: func(rom *Rom) { rom.Mapper = 256 },
+		},
+		{
+			name:   "trainer of the wrong size",
+			modify: func(rom *Rom) { rom.Trainer = make([]byte, 10) },
+		},
+		{
+			name:   "unknown mirroring",
+			modify: func(rom *Rom) { rom.Mirroring = 2 },
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			modified := rom
+			tt2.modify(&modified)
+
+			if _, err := Encode(modified); !errors.Is(err, ErrUnencodable) {
+				t.Errorf("Encode() error = %v, want %v", err, ErrUnencodable)
+			}
+		})
+	}
+}
+
+func Test_encodeROMSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		size      int
+		unit      int
+		wantLSB   byte
+		wantByte9 byte
+	}{
+		{
+			name:      "32 KiB PRG-ROM in 16 KiB units",
+			size:      32768,
+			unit:      16384,
+			wantLSB:   2,
+			wantByte9: 0,
+		},
+		{
+			name:      "64 MiB PRG-ROM in exponent-multiplier notation",
+			size:      4096 * 16384,
+			unit:      16384,
+			wantLSB:   26 << 2,
+			wantByte9: 0x0F,
+		},
+		{
+			name:      "48 byte PRG-ROM in exponent-multiplier notation",
+			size:      3 << 4,
+			unit:      16384,
+			wantLSB:   4<<2 | 1,
+			wantByte9: 0x0F,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			lsb, byte9, err := encodeROMSize(tt2.size, tt2.unit, 0, 0, false)
+			if err != nil {
+				t.Fatalf("encodeROMSize() error = %v", err)
+			}
+
+			if lsb != tt2.wantLSB || byte9 != tt2.wantByte9 {
+				t.Errorf("encodeROMSize() = %#x, %#x, want %#x, %#x", lsb, byte9, tt2.wantLSB, tt2.wantByte9)
+			}
+
+			if got := decodeROMSize(lsb, readLowNibbleByte(byte9), tt2.unit); got != tt2.size {
+				t.Errorf("decodeROMSize() = %v, want %v", got, tt2.size)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package ines
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoHeader is returned when the input does not start with 'NES^Z'.
+	ErrNoHeader = errors.New("no iNES header found")
+
+	// ErrTruncatedHeader is returned when the input starts with 'NES^Z' but is shorter than the 16-byte header.
+	ErrTruncatedHeader = errors.New("truncated iNES header")
+
+	// ErrUnencodable is returned when a Rom field cannot be expressed in the header.
+	ErrUnencodable = errors.New("value cannot be encoded in the header")
+)
+
+// ErrSectionOutOfBounds is returned when the header declares a section which does not fit in the file.
+// Want is the size of the section in bytes, Have is the number of bytes left in the file for it.
+type ErrSectionOutOfBounds struct {
+	Section string
+	Want    int
+	Have    int
+}
+
+func (e ErrSectionOutOfBounds) Error() string {
+	return fmt.Sprintf("section %v is out of bounds: want %v bytes, have %v", e.Section, e.Want, e.Have)
+}
+
+// section returns size bytes of b starting at start.
+// It returns ErrSectionOutOfBounds if b is too short to hold them.
+func section(name string, b []byte, start int, size int) ([]byte, error) {
+	if start > len(b) || size < 0 || size > len(b)-start {
+		have := len(b) - start
+		if have < 0 {
+			have = 0
+		}
+
+		return nil, ErrSectionOutOfBounds{Section: name, Want: size, Have: have}
+	}
+
+	return b[start : start+size], nil
+}
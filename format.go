@@ -0,0 +1,123 @@
+package ines
+
+// Format is the flavour of the header, which tells which of its bytes can be trusted.
+// https://wiki.nesdev.org/w/index.php/INES#Variant_comparison
+type Format int
+
+const (
+	FormatINES        Format = iota // iNES 1.0, bytes 0-10 are meaningful
+	FormatNES2                      // NES 2.0, all the 16 bytes are meaningful
+	FormatINES07                    // iNES 0.7, bytes 8-15 are unreliable
+	FormatArchaicINES               // Archaic iNES, bytes 7-15 are unreliable
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatINES:
+		return "iNES 1.0"
+	case FormatNES2:
+		return "iNES 2.0"
+	case FormatINES07:
+		return "iNES 0.7"
+	case FormatArchaicINES:
+		return "Archaic iNES"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// definedBytes returns the number of header bytes the format gives a meaning to.
+// The bytes which follow them are often garbage, like "DiskDude!" or the tag of the ripper.
+// nolint: gomnd
+func (f Format) definedBytes() int {
+	switch f {
+	case FormatNES2:
+		return 16
+	case FormatINES07:
+		return 8
+	case FormatArchaicINES:
+		return 7
+	default:
+		return 11
+	}
+}
+
+// DetectFormat returns the format of the header of b, following the detection rules of nesdev:
+//
+// If byte 7 AND $0C = $08, and the size taking into account byte 9 does not exceed the actual size of the ROM image,
+// then NES 2.0.
+// If byte 7 AND $0C = $04, archaic iNES.
+// If byte 7 AND $0C = $00, and bytes 12-15 are all 0, then iNES.
+// Otherwise, iNES 0.7 or archaic iNES.
+//
+// The last case is told apart by the bits 2-3 of byte 7: iNES 0.7 leaves them clear.
+func DetectFormat(b []byte) (Format, error) {
+	if !hasHeader(b) {
+		return 0, ErrNoHeader
+	}
+
+	if len(b) < headerSize {
+		return 0, ErrTruncatedHeader
+	}
+
+	var header Header
+
+	copy(header[:], b)
+
+	return detectFormat(header, len(b)), nil
+}
+
+// nolint: gomnd
+func detectFormat(header Header, size int) Format {
+	switch header[7] & 0b00001100 {
+	case 0b00001000:
+		trainer := 0
+		if header.HasTrainer() {
+			trainer = 512
+		}
+
+		romSize := headerSize + trainer + header.PRGROMSize() + header.CHRROMSize()
+		if header.PRGROMSize() >= 0 && header.CHRROMSize() >= 0 && romSize <= size {
+			return FormatNES2
+		}
+	case 0b00000100:
+		return FormatArchaicINES
+	case 0b00000000:
+		if header[12] == 0 && header[13] == 0 && header[14] == 0 && header[15] == 0 {
+			return FormatINES
+		}
+
+		return FormatINES07
+	}
+
+	return FormatArchaicINES
+}
+
+// cleanHeader returns header with the bytes the format gives no meaning to set to zero.
+func cleanHeader(header Header, format Format) Header {
+	for i := format.definedBytes(); i < headerSize; i++ {
+		header[i] = 0
+	}
+
+	return header
+}
+
+// mergeHeader returns the bytes the format gives a meaning to from header, and the others from raw.
+func mergeHeader(raw Header, header Header, format Format) Header {
+	copy(raw[:format.definedBytes()], header[:])
+
+	return raw
+}
+
+// CleanHeader zeroes the bytes of the header which the format of rom gives no meaning to,
+// like "DiskDude!" or the tag of the ripper. The archaic iNES and iNES 0.7 formats become iNES 1.0.
+func CleanHeader(rom Rom) (Rom, error) {
+	cleaned := rom
+	cleaned.Header = cleanHeader(rom.Header, rom.HeaderType)
+
+	if rom.HeaderType == FormatArchaicINES || rom.HeaderType == FormatINES07 {
+		cleaned.HeaderType = FormatINES
+	}
+
+	return reencode(cleaned)
+}
@@ -0,0 +1,97 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"testing"
+)
+
+// nolint: gochecknoglobals
+var diskDudeHeader = []byte{78, 69, 83, 26, 2, 1, 0x40, 'D', 'i', 's', 'k', 'D', 'u', 'd', 'e', '!'}
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		b    []byte
+		want Format
+	}{
+		{
+			name: "ines 1.0",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 0x10, 0x40, 0, 1, 0, 0, 0, 0, 0, 0}, 16384, 8192),
+			want: FormatINES,
+		},
+		{
+			name: "ines 2.0",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0x08, 0, 0, 0, 0, 0, 0, 0, 0}, 16384, 8192),
+			want: FormatNES2,
+		},
+		{
+			name: "ines 2.0 larger than the file",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0x08, 0, 0x01, 0, 0, 0, 0, 0, 0}, 16384, 8192),
+			want: FormatArchaicINES,
+		},
+		{
+			name: "diskdude",
+			b:    rawRom(diskDudeHeader, 32768, 8192),
+			want: FormatArchaicINES,
+		},
+		{
+			name: "ines 0.7 with a ripper tag",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0, 0, 0, 0, 0, 'r', 'i', 'p', 0}, 16384, 8192),
+			want: FormatINES07,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DetectFormat(tt2.b)
+			if err != nil {
+				t.Fatalf("DetectFormat() error = %v", err)
+			}
+
+			if got != tt2.want {
+				t.Errorf("DetectFormat() = %v, want %v", got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestDecode_diskDude(t *testing.T) {
+	t.Parallel()
+
+	b := rawRom(diskDudeHeader, 32768, 8192)
+
+	rom, err := Decode(b)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	// "D" in byte 7 would make it mapper 68
+	if rom.Mapper != 4 || rom.HeaderType != FormatArchaicINES {
+		t.Errorf("Decode() mapper = %v, format = %v, want 4, %v", rom.Mapper, rom.HeaderType, FormatArchaicINES)
+	}
+
+	got, err := Encode(rom)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if !bytes.Equal(got, b) {
+		t.Errorf("Encode() header = % x, want % x", got[:16], b[:16])
+	}
+
+	cleaned, err := CleanHeader(rom)
+	if err != nil {
+		t.Fatalf("CleanHeader() error = %v", err)
+	}
+
+	want := Header{78, 69, 83, 26, 2, 1, 0x40}
+	if cleaned.Header != want || cleaned.HeaderType != FormatINES || cleaned.Mapper != 4 {
+		t.Errorf("CleanHeader() header = % x, format = %v, mapper = %v, want % x, %v, 4",
+			cleaned.Header, cleaned.HeaderType, cleaned.Mapper, want, FormatINES)
+	}
+}
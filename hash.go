@@ -0,0 +1,93 @@
+package ines
+
+import (
+	"crypto/md5"  // nolint: gosec
+	"crypto/sha1" // nolint: gosec
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// Digest holds the hashes which ROM databases, like No-Intro, GoodNES or the NES 2.0 DB, identify data by.
+type Digest struct {
+	Size   int
+	CRC32  uint32
+	MD5    [md5.Size]byte
+	SHA1   [sha1.Size]byte
+	SHA256 [sha256.Size]byte
+}
+
+// Hashes holds the digests of the whole file and of its sections.
+// Databases use the headerless one to identify a game regardless of its header,
+// or the PRG-ROM and CHR-ROM ones to identify the chips.
+type Hashes struct {
+	File       Digest
+	Headerless Digest
+	Trainer    Digest
+	PRG        Digest
+	CHR        Digest
+}
+
+// digester computes all the hashes of a Digest at once.
+type digester struct {
+	size   int
+	crc32  hash.Hash32
+	md5    hash.Hash
+	sha1   hash.Hash
+	sha256 hash.Hash
+}
+
+func newDigester() *digester {
+	return &digester{
+		crc32:  crc32.NewIEEE(),
+		md5:    md5.New(),  // nolint: gosec
+		sha1:   sha1.New(), // nolint: gosec
+		sha256: sha256.New(),
+	}
+}
+
+func (d *digester) Write(p []byte) (int, error) {
+	d.size += len(p)
+
+	return io.MultiWriter(d.crc32, d.md5, d.sha1, d.sha256).Write(p)
+}
+
+func (d *digester) digest() Digest {
+	digest := Digest{Size: d.size, CRC32: d.crc32.Sum32()}
+
+	copy(digest.MD5[:], d.md5.Sum(nil))
+	copy(digest.SHA1[:], d.sha1.Sum(nil))
+	copy(digest.SHA256[:], d.sha256.Sum(nil))
+
+	return digest
+}
+
+// Hash computes the hashes of rom, reading each byte once.
+// The file is the header, as found in the file, followed by the headerless data.
+// The headerless data is the trainer, PRG-ROM and CHR-ROM followed by whatever comes after them in Headerless,
+// so that it is the same as the file without its first 16 bytes for a decoded rom.
+func Hash(rom Rom) Hashes {
+	file, headerless := newDigester(), newDigester()
+	trainer, prg, chr := newDigester(), newDigester(), newDigester()
+
+	rest := len(rom.Trainer) + len(rom.ProgramRom) + len(rom.CharacterRom)
+	if rest > len(rom.Headerless) {
+		rest = len(rom.Headerless)
+	}
+
+	// Hash writers never return an error
+	_, _ = file.Write(rom.Header[:])
+	_, _ = io.MultiWriter(file, headerless, trainer).Write(rom.Trainer)
+	_, _ = io.MultiWriter(file, headerless, prg).Write(rom.ProgramRom)
+	_, _ = io.MultiWriter(file, headerless, chr).Write(rom.CharacterRom)
+	_, _ = io.MultiWriter(file, headerless).Write(rom.Headerless[rest:])
+
+	return Hashes{
+		File:       file.digest(),
+		Headerless: headerless.digest(),
+		Trainer:    trainer.digest(),
+		PRG:        prg.digest(),
+		CHR:        chr.digest(),
+	}
+}
@@ -0,0 +1,67 @@
+package ines // nolint: testpackage
+
+import (
+	"crypto/md5"  // nolint: gosec
+	"crypto/sha1" // nolint: gosec
+	"crypto/sha256"
+	"hash/crc32"
+	"testing"
+)
+
+func digestOf(b []byte) Digest {
+	return Digest{
+		Size:   len(b),
+		CRC32:  crc32.ChecksumIEEE(b),
+		MD5:    md5.Sum(b),  // nolint: gosec
+		SHA1:   sha1.Sum(b), // nolint: gosec
+		SHA256: sha256.Sum256(b),
+	}
+}
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{
+			name: "ines 1.0 demo",
+			b:    demo,
+		},
+		{
+			name: "ines 1.0 with trainer and title",
+			b:    rawRom([]byte{78, 69, 83, 26, 1, 1, 0x04, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 512, 16384, 8192, 128),
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			got := Hash(rom)
+			want := Hashes{
+				File:       digestOf(tt2.b),
+				Headerless: digestOf(tt2.b[16:]),
+				Trainer:    digestOf(rom.Trainer),
+				PRG:        digestOf(rom.ProgramRom),
+				CHR:        digestOf(rom.CharacterRom),
+			}
+
+			if got != want {
+				t.Errorf("Hash() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -4,11 +4,16 @@ package ines
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
+	"math/bits"
 )
 
+// headerSize is the size of both iNES and NES 2.0 headers.
+const headerSize = 16
+
 // hasHeader returns true if input starts with 'NES^Z' (Hex equiv: 0x4e 0x45 0x53 0x1a).
 func hasHeader(b []byte) bool {
-	return bytes.HasPrefix(b, bytes.Trim(hexBytes("4e 45 53 1a"), " "))
+	return bytes.HasPrefix(b, hexBytes("4e45531a"))
 }
 
 // isINES2 returns true if the 7th Byte has bit-3 set and bit-2 off.
@@ -22,6 +27,572 @@ func hasBit(b byte, p uint8) bool {
 	return b&(1<<p) > 0
 }
 
+// setBit returns b with bit-p set to v.
+func setBit(b byte, p uint8, v bool) byte {
+	if v {
+		return b | 1<<p
+	}
+
+	return b &^ (1 << p)
+}
+
 func hexBytes(h string) []byte {
 	return func(b []byte, _ error) []byte { return b }(hex.DecodeString(h))
 }
+
+// Header is the 16-byte header of an iNES 1.0 or NES 2.0 file.
+// Its methods decode and encode the header fields at the bit level. Fields whose
+// meaning depends on the format follow the format given by IsNES2.
+type Header [headerSize]byte
+
+// NewHeader returns an empty iNES 1.0 header which starts with 'NES^Z'.
+func NewHeader() Header {
+	var h Header
+
+	copy(h[:], hexBytes("4e45531a"))
+
+	return h
+}
+
+// HasMagic returns true if the header starts with 'NES^Z'.
+func (h Header) HasMagic() bool {
+	return hasHeader(h[:])
+}
+
+// IsNES2 returns true if Header byte 7 identifies a NES 2.0 header.
+func (h Header) IsNES2() bool {
+	return isINES2(h[:])
+}
+
+// SetNES2 sets bits 2-3 of Header byte 7 to 10 for a NES 2.0 header, or clears them for iNES 1.0.
+// The other fields are not converted, see ToNES2 and ToINES for that.
+// nolint: gomnd
+func (h *Header) SetNES2(nes2 bool) {
+	h[7] &^= 0b00001100
+	if nes2 {
+		h[7] |= 0b00001000
+	}
+}
+
+/*	PRGROMSize
+	PRG-ROM Area
+	------------
+	The PRG-ROM Area follows the 16-byte Header and the Trainer Area and precedes the CHR-ROM Area.
+	Header byte 4 (LSB) and bits 0-3 of Header byte 9 (MSB) together specify its size.
+	If the MSB nibble is $0-E, LSB and MSB together simply specify the PRG-ROM size in 16 KiB units.
+	If the MSB nibble is $F, an exponent-multiplier notation is used instead.
+	iNES 1.0 only has the LSB.
+*/
+// nolint: gomnd
+func (h Header) PRGROMSize() int {
+	if !h.IsNES2() {
+		return int(h[4]) * 16384
+	}
+
+	return decodeROMSize(h[4], readLowNibbleByte(h[9]), 16384)
+}
+
+// SetPRGROMSize sets the PRG-ROM size in bytes.
+// NES 2.0 uses the exponent-multiplier notation for sizes which are not a multiple of 16 KiB.
+// nolint: gomnd
+func (h *Header) SetPRGROMSize(size int) error {
+	if !h.IsNES2() {
+		if size < 0 || size%16384 != 0 || size/16384 > 0xFF {
+			return fmt.Errorf("%w: PRG-ROM size %v in iNES 1.0", ErrUnencodable, size)
+		}
+
+		h[4] = byte(size / 16384)
+
+		return nil
+	}
+
+	lsb, byte9, err := encodeROMSize(size, 16384, h[4], h[9], false)
+	if err != nil {
+		return fmt.Errorf("PRG-ROM: %w", err)
+	}
+
+	h[4], h[9] = lsb, byte9
+
+	return nil
+}
+
+/*	CHRROMSize
+	CHR-ROM Area
+	------------
+	The CHR-ROM Area, if present, follows the Trainer and PRG-ROM Areas and precedes the Miscellaneous ROM Area.
+	Header byte 5 (LSB) and bits 4-7 of Header byte 9 (MSB) specify its size.
+	If the MSB nibble is $0-E, LSB and MSB together simply specify the CHR-ROM size in 8 KiB units.
+	If the MSB nibble is $F, an exponent-multiplier notation is used instead.
+	iNES 1.0 only has the LSB.
+*/
+// nolint: gomnd
+func (h Header) CHRROMSize() int {
+	if !h.IsNES2() {
+		return int(h[5]) * 8192
+	}
+
+	return decodeROMSize(h[5], readHighNibbleByte(h[9]), 8192)
+}
+
+// SetCHRROMSize sets the CHR-ROM size in bytes.
+// NES 2.0 uses the exponent-multiplier notation for sizes which are not a multiple of 8 KiB.
+// nolint: gomnd
+func (h *Header) SetCHRROMSize(size int) error {
+	if !h.IsNES2() {
+		if size < 0 || size%8192 != 0 || size/8192 > 0xFF {
+			return fmt.Errorf("%w: CHR-ROM size %v in iNES 1.0", ErrUnencodable, size)
+		}
+
+		h[5] = byte(size / 8192)
+
+		return nil
+	}
+
+	lsb, byte9, err := encodeROMSize(size, 8192, h[5], h[9], true)
+	if err != nil {
+		return fmt.Errorf("CHR-ROM: %w", err)
+	}
+
+	h[5], h[9] = lsb, byte9
+
+	return nil
+}
+
+// Mirroring returns the mirroring given by bits 0 and 3 of Header byte 6.
+// Header Byte 6 bit 0 is relevant only if the mapper does not allow the mirroring type to be switched.
+// Otherwise, it must be ignored and should be set to zero.
+// nolint: gomnd
+func (h Header) Mirroring() Mirroring {
+	if hasBit(h[6], 3) {
+		return MirroringFourScreen //  Ignore mirroring control and the mirroring bit
+	}
+
+	if hasBit(h[6], 0) {
+		return MirroringVertical
+	}
+
+	return MirroringHorizontal
+}
+
+// SetMirroring sets the mirroring bits of Header byte 6.
+// Bit 0 is ignored when bit 3 (four-screen) is set, so it is left untouched.
+// nolint: gomnd
+func (h *Header) SetMirroring(mirroring Mirroring) error {
+	switch mirroring {
+	case MirroringVertical:
+		h[6] = setBit(setBit(h[6], 0, true), 3, false)
+	case MirroringHorizontal:
+		h[6] = setBit(setBit(h[6], 0, false), 3, false)
+	case MirroringFourScreen:
+		h[6] = setBit(h[6], 3, true)
+	default:
+		return fmt.Errorf("%w: mirroring %v", ErrUnencodable, int(mirroring))
+	}
+
+	return nil
+}
+
+// HasBattery returns true if bit 1 of Header byte 6 is set.
+// It means there is a battery or another kind of non-volatile memory.
+func (h Header) HasBattery() bool {
+	return hasBit(h[6], 1)
+}
+
+// SetBattery sets bit 1 of Header byte 6.
+func (h *Header) SetBattery(battery bool) {
+	h[6] = setBit(h[6], 1, battery)
+}
+
+// HasTrainer returns true if bit 2 of Header byte 6 is set.
+// nolint: gomnd
+func (h Header) HasTrainer() bool {
+	return hasBit(h[6], 2)
+}
+
+// SetTrainer sets bit 2 of Header byte 6.
+// nolint: gomnd
+func (h *Header) SetTrainer(trainer bool) {
+	h[6] = setBit(h[6], 2, trainer)
+}
+
+// ConsoleType returns the console type given by bits 0-1 of Header byte 7.
+// iNES 1.0 gives precedence to the Vs. System bit.
+// In NES 2.0, the value 3 stands for the extended console type in the low nibble of Header byte 13.
+// nolint: gomnd
+func (h Header) ConsoleType() ConsoleType {
+	if !h.IsNES2() {
+		switch {
+		case hasBit(h[7], 0):
+			return ConsoleTypeVs
+		case hasBit(h[7], 1):
+			return ConsoleTypePlayChoice
+		default:
+			return ConsoleTypeNES
+		}
+	}
+
+	if consoleType := h[7] & 0b00000011; consoleType != 3 {
+		return ConsoleType(consoleType)
+	}
+
+	return ConsoleType(readLowNibbleByte(h[13]))
+}
+
+// SetConsoleType sets bits 0-1 of Header byte 7, and the low nibble of Header byte 13 for the extended
+// console types. Byte 13 is cleared when an extended console type is replaced, as it is only left to the
+// Vs. System. The header is kept as it is if it already gives consoleType.
+// iNES 1.0 only knows about the NES, the Vs. System and the PlayChoice-10.
+// nolint: gomnd
+func (h *Header) SetConsoleType(consoleType ConsoleType) error {
+	extended := h.IsNES2() && h[7]&0b00000011 == 3
+
+	switch {
+	case consoleType < 0 || consoleType > 0x0F:
+		return fmt.Errorf("%w: console type %v", ErrUnencodable, int(consoleType))
+	case h.ConsoleType() == consoleType:
+		return nil
+	case consoleType <= ConsoleTypePlayChoice:
+		h[7] = h[7]&^0b00000011 | byte(consoleType)
+
+		if extended {
+			h[13] = 0
+		}
+	case !h.IsNES2():
+		return fmt.Errorf("%w: console type %v in iNES 1.0", ErrUnencodable, int(consoleType))
+	default:
+		h[7] |= 0b00000011
+		h[13] = mergeNibbles(readHighNibbleByte(h[13]), byte(consoleType))
+	}
+
+	return nil
+}
+
+// Mapper returns the mapper number.
+// Its bits 0-3 are in the high nibble of Header byte 6 and bits 4-7 in the high nibble of Header byte 7.
+// NES 2.0 adds bits 8-11 in the low nibble of Header byte 8.
+// nolint: gomnd
+func (h Header) Mapper() int {
+	mapper := int(mergeNibbles(readHighNibbleByte(h[7]), readHighNibbleByte(h[6])))
+	if h.IsNES2() {
+		mapper |= int(readLowNibbleByte(h[8])) << 8
+	}
+
+	return mapper
+}
+
+// SetMapper sets the mapper number, which is at most 255 in iNES 1.0 and 4095 in NES 2.0.
+// nolint: gomnd
+func (h *Header) SetMapper(mapper int) error {
+	if mapper < 0 || mapper > 0xFFF || (!h.IsNES2() && mapper > 0xFF) {
+		return fmt.Errorf("%w: mapper %v", ErrUnencodable, mapper)
+	}
+
+	h[6] = mergeNibbles(byte(mapper)&0x0F, readLowNibbleByte(h[6]))
+	h[7] = mergeNibbles(byte(mapper>>4)&0x0F, readLowNibbleByte(h[7]))
+
+	if h.IsNES2() {
+		h[8] = mergeNibbles(readHighNibbleByte(h[8]), byte(mapper>>8))
+	}
+
+	return nil
+}
+
+// SubMapper returns the NES 2.0 submapper number in the high nibble of Header byte 8.
+// It is always 0 for iNES 1.0, whose Rom.SubMapper is SubMapperUnspecified.
+func (h Header) SubMapper() int {
+	if !h.IsNES2() {
+		return 0
+	}
+
+	return int(readHighNibbleByte(h[8]))
+}
+
+// SetSubMapper sets the NES 2.0 submapper number.
+// nolint: gomnd
+func (h *Header) SetSubMapper(subMapper int) error {
+	if subMapper < 0 || subMapper > 0x0F || (!h.IsNES2() && subMapper != 0) {
+		return fmt.Errorf("%w: submapper %v", ErrUnencodable, subMapper)
+	}
+
+	if h.IsNES2() {
+		h[8] = mergeNibbles(byte(subMapper), readLowNibbleByte(h[8]))
+	}
+
+	return nil
+}
+
+// PRGRAMShift returns the NES 2.0 PRG-RAM (volatile) shift count in the low nibble of Header byte 10.
+func (h Header) PRGRAMShift() int {
+	return int(readLowNibbleByte(h[10]))
+}
+
+// SetPRGRAMShift sets the NES 2.0 PRG-RAM (volatile) shift count.
+func (h *Header) SetPRGRAMShift(shift int) error {
+	return h.setNibble(10, false, shift, "PRG-RAM shift count")
+}
+
+// PRGNVRAMShift returns the NES 2.0 PRG-NVRAM/EEPROM (non-volatile) shift count in the high nibble of Header byte 10.
+func (h Header) PRGNVRAMShift() int {
+	return int(readHighNibbleByte(h[10]))
+}
+
+// SetPRGNVRAMShift sets the NES 2.0 PRG-NVRAM/EEPROM (non-volatile) shift count.
+func (h *Header) SetPRGNVRAMShift(shift int) error {
+	return h.setNibble(10, true, shift, "PRG-NVRAM shift count")
+}
+
+// CHRRAMShift returns the NES 2.0 CHR-RAM (volatile) shift count in the low nibble of Header byte 11.
+// In NES 1.0 an emulator assumes that a ROM image without CHR-ROM, automatically has 8 KiB of CHR-RAM;
+// But in NES 2.0 all CHR-RAM must instead be explicitly specified here.
+func (h Header) CHRRAMShift() int {
+	return int(readLowNibbleByte(h[11]))
+}
+
+// SetCHRRAMShift sets the NES 2.0 CHR-RAM (volatile) shift count.
+func (h *Header) SetCHRRAMShift(shift int) error {
+	return h.setNibble(11, false, shift, "CHR-RAM shift count")
+}
+
+// CHRNVRAMShift returns the NES 2.0 CHR-NVRAM (non-volatile) shift count in the high nibble of Header byte 11.
+func (h Header) CHRNVRAMShift() int {
+	return int(readHighNibbleByte(h[11]))
+}
+
+// SetCHRNVRAMShift sets the NES 2.0 CHR-NVRAM (non-volatile) shift count.
+func (h *Header) SetCHRNVRAMShift(shift int) error {
+	return h.setNibble(11, true, shift, "CHR-NVRAM shift count")
+}
+
+// PRGRAMSize returns the NES 2.0 PRG-RAM size in bytes.
+func (h Header) PRGRAMSize() int {
+	return shiftSize(h.PRGRAMShift())
+}
+
+// PRGNVRAMSize returns the NES 2.0 PRG-NVRAM/EEPROM size in bytes.
+func (h Header) PRGNVRAMSize() int {
+	return shiftSize(h.PRGNVRAMShift())
+}
+
+// CHRRAMSize returns the NES 2.0 CHR-RAM size in bytes.
+func (h Header) CHRRAMSize() int {
+	return shiftSize(h.CHRRAMShift())
+}
+
+// CHRNVRAMSize returns the NES 2.0 CHR-NVRAM size in bytes.
+func (h Header) CHRNVRAMSize() int {
+	return shiftSize(h.CHRNVRAMShift())
+}
+
+// TVSystem returns the TV system.
+// iNES 1.0 gives it in bit 0 of Header byte 9, NES 2.0 in bits 0-1 of Header byte 12.
+// According to the official specification very few emulators honor the iNES 1.0 bit.
+// nolint: gomnd
+func (h Header) TVSystem() TVSystem {
+	if h.IsNES2() {
+		return TVSystem(h[12] & 0b00000011)
+	}
+
+	if hasBit(h[9], 0) {
+		return TVSystemPAL
+	}
+
+	return TVSystemNTSC
+}
+
+// SetTVSystem sets the TV system. iNES 1.0 only knows about NTSC and PAL.
+// In NES 2.0 this is the same as setting the CPU/PPU timing.
+// nolint: gomnd
+func (h *Header) SetTVSystem(tvSystem TVSystem) error {
+	if h.IsNES2() {
+		return h.SetCPUPPUTiming(CPUPPUTiming(tvSystem))
+	}
+
+	if tvSystem != TVSystemNTSC && tvSystem != TVSystemPAL {
+		return fmt.Errorf("%w: TV system %v in iNES 1.0", ErrUnencodable, int(tvSystem))
+	}
+
+	h[9] = setBit(h[9], 0, tvSystem == TVSystemPAL)
+
+	return nil
+}
+
+// CPUPPUTiming returns the NES 2.0 CPU/PPU timing in bits 0-1 of Header byte 12.
+// It is unknown for iNES 1.0.
+// nolint: gomnd
+func (h Header) CPUPPUTiming() CPUPPUTiming {
+	if !h.IsNES2() {
+		return CPUPPUTimingUnknown
+	}
+
+	return CPUPPUTiming(h[12] & 0b00000011)
+}
+
+// SetCPUPPUTiming sets the NES 2.0 CPU/PPU timing.
+// nolint: gomnd
+func (h *Header) SetCPUPPUTiming(timing CPUPPUTiming) error {
+	if timing < 0 || timing > 3 || !h.IsNES2() {
+		return fmt.Errorf("%w: CPU/PPU timing %v", ErrUnencodable, int(timing))
+	}
+
+	h[12] = h[12]&^0b00000011 | byte(timing)
+
+	return nil
+}
+
+// VsPPUType returns the NES 2.0 Vs. System PPU type in the low nibble of Header byte 13.
+func (h Header) VsPPUType() VsPPUType {
+	return VsPPUType(readLowNibbleByte(h[13]))
+}
+
+// SetVsPPUType sets the NES 2.0 Vs. System PPU type.
+func (h *Header) SetVsPPUType(ppu VsPPUType) error {
+	return h.setNibble(13, false, int(ppu), "Vs. System PPU")
+}
+
+// VsSystemType returns the NES 2.0 Vs. System hardware type in the high nibble of Header byte 13.
+func (h Header) VsSystemType() VsSystemType {
+	return VsSystemType(readHighNibbleByte(h[13]))
+}
+
+// SetVsSystemType sets the NES 2.0 Vs. System hardware type.
+func (h *Header) SetVsSystemType(vsType VsSystemType) error {
+	return h.setNibble(13, true, int(vsType), "Vs. System type")
+}
+
+// MiscROMCount returns the number of NES 2.0 Miscellaneous ROMs in bits 0-1 of Header byte 14.
+// nolint: gomnd
+func (h Header) MiscROMCount() int {
+	return int(h[14] & 0b00000011)
+}
+
+// SetMiscROMCount sets the number of NES 2.0 Miscellaneous ROMs, which is at most 3.
+// nolint: gomnd
+func (h *Header) SetMiscROMCount(count int) error {
+	if count < 0 || count > 3 {
+		return fmt.Errorf("%w: miscellaneous ROM count %v", ErrUnencodable, count)
+	}
+
+	h[14] = h[14]&^0b00000011 | byte(count)
+
+	return nil
+}
+
+// ExpansionDevice returns the NES 2.0 default expansion device in bits 0-5 of Header byte 15.
+// nolint: gomnd
+func (h Header) ExpansionDevice() ExpansionDevice {
+	return ExpansionDevice(h[15] & 0b00111111)
+}
+
+// SetExpansionDevice sets the NES 2.0 default expansion device.
+// nolint: gomnd
+func (h *Header) SetExpansionDevice(device ExpansionDevice) error {
+	if device < 0 || device > 0x3F {
+		return fmt.Errorf("%w: expansion device %v", ErrUnencodable, int(device))
+	}
+
+	h[15] = h[15]&^0b00111111 | byte(device)
+
+	return nil
+}
+
+// setNibble sets the high or low nibble of Header byte i to v.
+// nolint: gomnd
+func (h *Header) setNibble(i int, high bool, v int, name string) error {
+	if v < 0 || v > 0x0F {
+		return fmt.Errorf("%w: %v %v", ErrUnencodable, name, v)
+	}
+
+	if high {
+		h[i] = mergeNibbles(byte(v), readLowNibbleByte(h[i]))
+	} else {
+		h[i] = mergeNibbles(readHighNibbleByte(h[i]), byte(v))
+	}
+
+	return nil
+}
+
+// shiftSize returns the size in bytes for a NES 2.0 shift count.
+// If the shift count is zero, there is no such memory.
+// nolint: gomnd
+func shiftSize(shift int) int {
+	if shift == 0 {
+		return 0
+	}
+
+	return 64 << shift // i.e. that is 8192 bytes for a shift count of 7.
+}
+
+// encodeROMSize returns the LSB byte and the MSB byte 9 for a NES 2.0 PRG-ROM or CHR-ROM size.
+// The MSB nibble lives in the high nibble of byte 9 when high is true, in the low nibble otherwise.
+// The current encoding is kept if it already gives size. Otherwise, the size is written in units,
+// or in the exponent-multiplier notation when it cannot be expressed in units.
+// nolint: gomnd
+func encodeROMSize(size int, unit int, lsb byte, byte9 byte, high bool) (byte, byte, error) {
+	if size < 0 {
+		return 0, 0, fmt.Errorf("%w: size %v", ErrUnencodable, size)
+	}
+
+	msbNibble := readLowNibbleByte(byte9)
+	if high {
+		msbNibble = readHighNibbleByte(byte9)
+	}
+
+	if decodeROMSize(lsb, msbNibble, unit) == size {
+		return lsb, byte9, nil
+	}
+
+	switch {
+	case size%unit == 0 && size/unit <= 0xEFF:
+		lsb, msbNibble = byte(size/unit), byte(size/unit>>8)&0x0F
+	case size > 0:
+		exponent := bits.TrailingZeros(uint(size))
+		multiplier := size >> exponent
+
+		if multiplier > 7 {
+			return 0, 0, fmt.Errorf("%w: size %v", ErrUnencodable, size)
+		}
+
+		lsb, msbNibble = byte(exponent<<2)|byte(multiplier>>1), 0x0F
+	default:
+		return 0, 0, fmt.Errorf("%w: size %v", ErrUnencodable, size)
+	}
+
+	if high {
+		return lsb, mergeNibbles(msbNibble, readLowNibbleByte(byte9)), nil
+	}
+
+	return lsb, mergeNibbles(readHighNibbleByte(byte9), msbNibble), nil
+}
+
+// decodeROMSize returns the size in bytes given by the LSB byte and the MSB nibble of a NES 2.0 ROM size.
+// nolint: gomnd
+func decodeROMSize(lsb byte, msbNibble byte, unit int) int {
+	if msbNibble == 0x0F {
+		exponent := lsb >> 2
+		multiplier := int(lsb&0b00000011)*2 + 1
+
+		if exponent > 60 { // would overflow
+			return -1
+		}
+
+		return (1 << exponent) * multiplier
+	}
+
+	return (int(msbNibble)<<8 | int(lsb)) * unit
+}
+
+// shiftCount returns the NES 2.0 shift count for a RAM size, which is 64 << shift count bytes.
+// nolint: gomnd
+func shiftCount(size int) (byte, error) {
+	if size == 0 {
+		return 0, nil
+	}
+
+	for shift := 1; shift <= 15; shift++ {
+		if 64<<shift == size {
+			return byte(shift), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: RAM size %v", ErrUnencodable, size)
+}
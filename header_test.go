@@ -97,3 +97,207 @@ func Test_isINES2(t *testing.T) {
 		})
 	}
 }
+
+func TestHeader_SetMapper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		nes2    bool
+		mapper  int
+		wantErr bool
+	}{
+		{name: "ines 1.0 mapper 4", mapper: 4},
+		{name: "ines 1.0 mapper 255", mapper: 255},
+		{name: "ines 1.0 mapper 256", mapper: 256, wantErr: true},
+		{name: "ines 2.0 mapper 256", nes2: true, mapper: 256},
+		{name: "ines 2.0 mapper 4095", nes2: true, mapper: 4095},
+		{name: "ines 2.0 mapper 4096", nes2: true, mapper: 4096, wantErr: true},
+		{name: "negative mapper", mapper: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := NewHeader()
+			header.SetNES2(tt2.nes2)
+
+			err := header.SetMapper(tt2.mapper)
+			if (err != nil) != tt2.wantErr {
+				t.Fatalf("SetMapper() error = %v, wantErr %v", err, tt2.wantErr)
+			}
+
+			if err == nil && header.Mapper() != tt2.mapper {
+				t.Errorf("Mapper() = %v, want %v", header.Mapper(), tt2.mapper)
+			}
+		})
+	}
+}
+
+func TestHeader_SetPRGROMSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		nes2      bool
+		size      int
+		wantByte4 byte
+		wantByte9 byte
+		wantErr   bool
+	}{
+		{name: "ines 1.0 32 KiB", size: 32768, wantByte4: 2},
+		{name: "ines 1.0 odd size", size: 100, wantErr: true},
+		{name: "ines 2.0 8 MiB in 16 KiB units", nes2: true, size: 512 * 16384, wantByte4: 0x00, wantByte9: 0x02},
+		{name: "ines 2.0 odd size in exponent-multiplier notation", nes2: true, size: 24576, wantByte4: 13<<2 | 1, wantByte9: 0x0F},
+		{name: "ines 2.0 size without exponent-multiplier notation", nes2: true, size: 9 * 16, wantErr: true},
+		{name: "ines 1.0 negative size", size: -16384, wantErr: true},
+		{name: "ines 2.0 negative size", nes2: true, size: -16384, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := NewHeader()
+			header.SetNES2(tt2.nes2)
+
+			err := header.SetPRGROMSize(tt2.size)
+			if (err != nil) != tt2.wantErr {
+				t.Fatalf("SetPRGROMSize() error = %v, wantErr %v", err, tt2.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if header[4] != tt2.wantByte4 || header[9] != tt2.wantByte9 {
+				t.Errorf("SetPRGROMSize() bytes 4, 9 = %#x, %#x, want %#x, %#x", header[4], header[9], tt2.wantByte4, tt2.wantByte9)
+			}
+
+			if header.PRGROMSize() != tt2.size {
+				t.Errorf("PRGROMSize() = %v, want %v", header.PRGROMSize(), tt2.size)
+			}
+		})
+	}
+}
+
+func TestHeader_SetCHRROMSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		nes2      bool
+		size      int
+		wantByte5 byte
+		wantByte9 byte
+		wantErr   bool
+	}{
+		{name: "ines 1.0 8 KiB", size: 8192, wantByte5: 1, wantByte9: 0x01},
+		{name: "ines 1.0 negative size", size: -8192, wantErr: true},
+		{name: "ines 2.0 2 MiB in 8 KiB units", nes2: true, size: 256 * 8192, wantByte5: 0x00, wantByte9: 0x11},
+		{name: "ines 2.0 negative size", nes2: true, size: -8192, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := NewHeader()
+			header.SetNES2(tt2.nes2)
+			header[9] = 0x01 // a PRG-ROM MSB nibble, which setting the CHR-ROM size must keep
+			before := header
+
+			err := header.SetCHRROMSize(tt2.size)
+			if (err != nil) != tt2.wantErr {
+				t.Fatalf("SetCHRROMSize() error = %v, wantErr %v", err, tt2.wantErr)
+			}
+
+			if err != nil {
+				if header != before {
+					t.Errorf("SetCHRROMSize() changed the header to % X on error", header[:])
+				}
+
+				return
+			}
+
+			if header[5] != tt2.wantByte5 || header[9] != tt2.wantByte9 {
+				t.Errorf("SetCHRROMSize() bytes 5, 9 = %#x, %#x, want %#x, %#x", header[5], header[9], tt2.wantByte5, tt2.wantByte9)
+			}
+
+			if header.CHRROMSize() != tt2.size {
+				t.Errorf("CHRROMSize() = %v, want %v", header.CHRROMSize(), tt2.size)
+			}
+		})
+	}
+}
+
+func TestHeader_nes2Fields(t *testing.T) {
+	t.Parallel()
+
+	header := Header{78, 69, 83, 26, 2, 1, 0x12, 0x48, 0x53, 0x10, 0x70, 0x07, 3, 0, 1, 0x2A}
+
+	if !header.IsNES2() {
+		t.Fatalf("IsNES2() = false, want true")
+	}
+
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "Mapper", got: header.Mapper(), want: 0x341},
+		{name: "SubMapper", got: header.SubMapper(), want: 5},
+		{name: "PRGROMSize", got: header.PRGROMSize(), want: 2 * 16384},
+		{name: "CHRROMSize", got: header.CHRROMSize(), want: 0x101 * 8192},
+		{name: "PRGRAMShift", got: header.PRGRAMShift(), want: 0},
+		{name: "PRGNVRAMShift", got: header.PRGNVRAMShift(), want: 7},
+		{name: "CHRRAMSize", got: header.CHRRAMSize(), want: 8192},
+		{name: "CPUPPUTiming", got: int(header.CPUPPUTiming()), want: int(CPUPPUTimingDendy)},
+		{name: "MiscROMCount", got: header.MiscROMCount(), want: 1},
+		{name: "ExpansionDevice", got: int(header.ExpansionDevice()), want: int(ExpansionDeviceMulticart)},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt2.got != tt2.want {
+				t.Errorf("%v() = %v, want %v", tt2.name, tt2.got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestHeader_SetConsoleType(t *testing.T) {
+	t.Parallel()
+
+	header := NewHeader()
+	header.SetNES2(true)
+
+	if err := header.SetConsoleType(ConsoleTypeVT369); err != nil {
+		t.Fatal(err)
+	}
+
+	if header[7]&3 != 3 || header[13] != 0x0A || header.ConsoleType() != ConsoleTypeVT369 {
+		t.Errorf("SetConsoleType(VT369) bytes 7, 13 = %#x, %#x, want bits 0-1 set and 0x0a", header[7], header[13])
+	}
+
+	if err := header.SetConsoleType(ConsoleTypeNES); err != nil {
+		t.Fatal(err)
+	}
+
+	if header[7]&3 != 0 || header[13] != 0 {
+		t.Errorf("SetConsoleType(NES) bytes 7, 13 = %#x, %#x, want byte 13 cleared", header[7], header[13])
+	}
+
+	header.SetNES2(false)
+
+	if err := header.SetConsoleType(ConsoleTypeVT03); err == nil {
+		t.Error("SetConsoleType(VT03) in iNES 1.0 error = nil, want ErrUnencodable")
+	}
+}
@@ -13,46 +13,71 @@ An iNES file consists of the following sections, in order:
 7. Some ROM-Images additionally contain a 128-byte (or sometimes 127-byte) title at the end of the file.
 */
 
-func parseINES(b []byte) Rom {
+// parseINES parses iNES 1.0, and its older iNES 0.7 and archaic iNES variants.
+// The header bytes which the format doesn't define are ignored, as they are often garbage.
+func parseINES(b []byte, format Format) (Rom, error) {
 	headerless := b[16:] // rom without header. It's useful for calculating checksums.
-	header := b[:16]     // header (16 bytes)
-	trainer := getTrainer(b, header)
-	prgrom := getPrgRom(header, headerless, trainer)
-	chrrom, sizeChrrom := getChrRomAndSize(header, headerless, trainer, prgrom)
+
+	var raw Header // header (16 bytes), as found in the file
+
+	copy(raw[:], b)
+
+	header := cleanHeader(raw, format)
+
+	trainer, err := getTrainer(b, header)
+	if err != nil {
+		return Rom{}, err
+	}
+
+	prgrom, err := getPrgRom(header, headerless, trainer)
+	if err != nil {
+		return Rom{}, err
+	}
+
+	chrrom, sizeChrrom, err := getChrRomAndSize(header, headerless, trainer, prgrom)
+	if err != nil {
+		return Rom{}, err
+	}
+
 	chrram := getChrRAM(sizeChrrom)
 
 	// nolint: lll
-	consoleType, playChoiceInstRom, playChoicePROMData, playChoiceRomCounterOut := getConsoleTypes(header, headerless, trainer, prgrom, chrrom)
+	consoleType, playChoiceInstRom, playChoicePROMData, playChoiceRomCounterOut, err := getConsoleTypes(header, headerless, trainer, prgrom, chrrom)
+	if err != nil {
+		return Rom{}, err
+	}
+
 	title := getTitle(headerless, trainer, prgrom, chrrom, playChoiceInstRom, playChoicePROMData, playChoiceRomCounterOut)
 	hasBatteryPrgRAM, prgram := getPrgRAMIfHasBattery(header)
-	mapper := getMapper(header)
-	mirroring := getMirroring(header)
-	tvSystem := getTvSystem(header)
 
 	return Rom{
-		HeaderType:      "iNES 1.0",
+		HeaderType:      format,
 		Headerless:      headerless,
-		Header:          header,
+		Header:          raw,
 		Trainer:         trainer,
 		ProgramRom:      prgrom,
 		CharacterRom:    chrrom,
 		HasBattery:      hasBatteryPrgRAM,
 		ProgramRAM:      prgram,
 		MiscRom:         []byte{},
-		Mapper:          mapper,
-		SubMapper:       0,
+		Mapper:          header.Mapper(),
+		SubMapper:       SubMapperUnspecified,
 		ConsoleType:     consoleType,
 		Title:           title,
-		TVSystem:        tvSystem,
-		Mirroring:       mirroring,
-		VsSystemPPU:     "Unknown",
-		VsSystemType:    "Unknown",
-		CPUPPUTiming:    "Unknown",
-		ExpansionDevice: "Unknown",
+		TVSystem:        header.TVSystem(),
+		Mirroring:       header.Mirroring(),
+		VsSystemPPU:     VsPPUTypeUnknown,
+		VsSystemType:    VsSystemTypeUnknown,
+		CPUPPUTiming:    CPUPPUTimingUnknown,
+		ExpansionDevice: ExpansionDeviceUnspecified,
 		CharacterRAM:    chrram,
 		CharacterNVRam:  []byte{},
 		ProgramNVRam:    []byte{},
-	}
+
+		PlayChoiceInstRom:        playChoiceInstRom,
+		PlayChoicePROMData:       playChoicePROMData,
+		PlayChoicePROMCounterOut: playChoiceRomCounterOut,
+	}, nil
 }
 
 // getTitle fetches the game's name
@@ -65,8 +90,8 @@ func parseINES(b []byte) Rom {
 // nolint: lll
 func getTitle(headerless []byte, trainer []byte, prgrom []byte, chrrom []byte, playChoiceInstRom []byte, playChoicePROMData []byte, playChoiceRomCounterOut []byte) []byte {
 	var title []byte
-	if leftover := len(headerless) - len(trainer) + len(prgrom) + len(chrrom) + len(playChoiceInstRom) + len(playChoicePROMData) + len(playChoiceRomCounterOut); leftover != 0 {
-		title = headerless[len(trainer)+len(prgrom)+len(chrrom)+len(playChoiceInstRom)+len(playChoicePROMData)+len(playChoiceRomCounterOut):]
+	if end := len(trainer) + len(prgrom) + len(chrrom) + len(playChoiceInstRom) + len(playChoicePROMData) + len(playChoiceRomCounterOut); end < len(headerless) {
+		title = headerless[end:]
 	}
 
 	return title
@@ -77,80 +102,56 @@ func getTitle(headerless []byte, trainer []byte, prgrom []byte, chrrom []byte, p
 // PlayChoice games are designed to look good with the 2C03 RGB PPU
 // which handles color emphasis differently from a standard NES PPU.
 // The detection of which palette a particular game uses is left unspecified.
-// If present, it's 8192 bytes.
-// nolint: lll, gomnd
-func getConsoleTypes(header []byte, headerless []byte, trainer []byte, prgrom []byte, chrrom []byte) (string, []byte, []byte, []byte) {
-	var (
-		consoleType             = nes
-		playChoiceInstRom       []byte
-		playChoicePROMData      []byte
-		playChoiceRomCounterOut []byte
-	)
-
-	if hasBit(header[7], 1) {
-		consoleType = playchoice
-		playChoiceInstRomSize := 8192
-		// 8 KB INST ROM (containing data and Z80 code for instruction screens)
-		playChoiceInstRom = headerless[len(trainer)+len(prgrom)+len(chrrom) : len(trainer)+len(prgrom)+len(chrrom)+playChoiceInstRomSize]
-
-		// PlayChoice PROM , if present (16 bytes Data, 16 bytes CounterOut)
-		// 16 bytes RP5H01 PROM Data output (needed to decrypt the INST ROM)
-		playChoicePROMDataSize := 8192 * 2
-		// nolint: lll
-		playChoicePROMData = headerless[len(trainer)+len(prgrom)+len(chrrom)+len(playChoiceInstRom) : len(trainer)+len(prgrom)+len(chrrom)+len(playChoiceInstRom)+playChoicePROMDataSize]
-
-		// 16 bytes RP5H01 PROM CounterOut output (needed to decrypt the INST ROM)
-		// usually constant: 00,00,00,00,FF,FF,FF,FF,00,00,00,00,FF,FF,FF,FF
-		playChoiceRomCounterOutSize := 8192 * 2
-		// nolint: lll
-		playChoiceRomCounterOut = headerless[len(trainer)+len(prgrom)+len(chrrom)+len(playChoiceInstRom)+len(playChoicePROMData) : len(trainer)+len(prgrom)+len(chrrom)+len(playChoiceInstRom)+len(playChoicePROMData)+playChoiceRomCounterOutSize]
+// If present, it's 8192 bytes, followed by the 16 bytes of PROM Data and the 16 bytes of PROM CounterOut,
+// which many dumps leave out.
+// nolint: lll
+func getConsoleTypes(header Header, headerless []byte, trainer []byte, prgrom []byte, chrrom []byte) (ConsoleType, []byte, []byte, []byte, error) {
+	consoleType := header.ConsoleType()
+
+	if !hasBit(header[7], 1) {
+		return consoleType, nil, nil, nil, nil
 	}
 
-	if hasBit(header[7], 0) {
-		consoleType = vs
+	// 8 KB INST ROM (containing data and Z80 code for instruction screens)
+	start := len(trainer) + len(prgrom) + len(chrrom)
+	if _, err := section("PlayChoice INST-ROM", headerless, start, pc10InstROMSize); err != nil {
+		return 0, nil, nil, nil, err
 	}
 
-	return consoleType, playChoiceInstRom, playChoicePROMData, playChoiceRomCounterOut
+	pc10, _ := splitPlayChoice10(headerless[start:])
+
+	return consoleType, pc10.InstROM, pc10.PROMData, pc10.PROMCounterOut, nil
 }
 
 // getPrgRom data (16384 * x bytes)
 // The PRG-ROM Area follows the Header and the Trainer and precedes the CHR-ROM Area.
-// Size of Program ROM (in 16 KB units).
-func getPrgRom(header []byte, headerless []byte, trainer []byte) []byte {
-	var prgrom []byte
-
-	sizePrgrom := int(header[4]) * 16384                        // nolint: gomnd
-	prgrom = headerless[len(trainer) : len(trainer)+sizePrgrom] // if trainer is 0, this will still work
-
-	return prgrom
+func getPrgRom(header Header, headerless []byte, trainer []byte) ([]byte, error) {
+	return section("PRG-ROM", headerless, len(trainer), header.PRGROMSize()) // if trainer is 0, this will still work
 }
 
 // getTrainer exists if bit 2 of Header byte 6 is set.
 // It contains data to be loaded into CPU memory at 0x7000
 // It is only used by some games that were modified to run on different hardware from the original cartridges,
 // such as early RAM cartridges and emulators, adding some compatibility code into those address ranges.
+// Trainer is placed between header and PRG ROM data, so PRG ROM should start in the next avail address.
 // nolint: gomnd
-func getTrainer(b []byte, header []byte) []byte {
-	var trainer []byte
-	if hasBit(header[6], 2) {
-		trainer = b[16:528] // starts from b[16] and has 512 bytes length, so it goes up to b[16+512]
+func getTrainer(b []byte, header Header) ([]byte, error) {
+	if header.HasTrainer() {
+		return section("Trainer", b, 16, 512) // starts from b[16] and has 512 bytes length, so it goes up to b[16+512]
 	}
 
-	return trainer
+	return nil, nil
 }
 
 // getChrRomAndSize The CHR-ROM Area, if present, follows the Trainer and PRG-ROM Areas
 // and precedes the PlayChoice INST-ROM Area.
 // CHR ROM data, if present (8192 * y bytes).
-// Size of Character ROM (in 8 KB units).
-func getChrRomAndSize(header []byte, headerless []byte, trainer []byte, prgrom []byte) ([]byte, int) {
-	var chrrom []byte
-
-	sizeChrrom := int(header[5]) * 8192 // nolint: gomnd
+func getChrRomAndSize(header Header, headerless []byte, trainer []byte, prgrom []byte) ([]byte, int, error) {
+	sizeChrrom := header.CHRROMSize()
 
-	chrrom = headerless[len(trainer)+len(prgrom) : len(trainer)+len(prgrom)+sizeChrrom]
+	chrrom, err := section("CHR-ROM", headerless, len(trainer)+len(prgrom), sizeChrrom)
 
-	return chrrom, sizeChrrom
+	return chrrom, sizeChrrom, err
 }
 
 // getChrRAM If CHR ROM size is 0; it means the board uses 8 KB CHR RAM
@@ -169,25 +170,12 @@ func getChrRAM(sizeChrrom int) []byte {
 	return chrram
 }
 
-// getTvSystem fetches the TV system
-// According to the official specification very few emulators honor this bit
-// virtually no ROM images in circulation make use of it.
-func getTvSystem(header []byte) string {
-	tvSystem := "NTSC"
-	if hasBit(header[9], 0) {
-		tvSystem = "PAL"
-	}
-
-	return tvSystem
-}
-
 // getPrgRAMIfHasBattery fetches Battery or any other non-volatile memory (PRG RAM).
-func getPrgRAMIfHasBattery(header []byte) (bool, []byte) {
+func getPrgRAMIfHasBattery(header Header) (bool, []byte) {
 	var prgRAMBatterySize int
 
-	hasBatteryPrgRAM := false
-	if hasBit(header[6], 1) {
-		hasBatteryPrgRAM = true
+	hasBatteryPrgRAM := header.HasBattery()
+	if hasBatteryPrgRAM {
 		// The PRG RAM Size value (stored in byte 8) was recently added to the official specification;
 		// as such, virtually no ROM images in circulation make use of it.
 		prgRAMBatterySize = 8192 // default is 8 KB
@@ -197,27 +185,3 @@ func getPrgRAMIfHasBattery(header []byte) (bool, []byte) {
 
 	return hasBatteryPrgRAM, prgram
 }
-
-// getMapper fetches the mapper.
-func getMapper(header []byte) int {
-	lowerNibbleMapper := readHighNibbleByte(header[6])
-	upperNibbleMapper := readHighNibbleByte(header[7])
-	mapper := byteToInt(mergeNibbles(upperNibbleMapper, lowerNibbleMapper))
-
-	return mapper
-}
-
-// getMirroring fetches the mirror value.
-// nolint: gomnd
-func getMirroring(header []byte) (mirroring string) {
-	if hasBit(header[6], 3) {
-		mirroring = "Four-screen VRAM" //  Ignore mirroring control and the mirroring bit
-	} else {
-		mirroring = "Horizontal or mapper controlled"
-		if hasBit(header[6], 0) {
-			mirroring = "Vertical"
-		}
-	}
-
-	return mirroring
-}
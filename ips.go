@@ -0,0 +1,187 @@
+package ines
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// IPS patches are a list of records, each of which writes data at an offset of the file:
+//
+//	"PATCH"
+//	offset (3 bytes, big-endian), size (2 bytes, big-endian), data (size bytes)
+//	offset (3 bytes, big-endian), 0 (2 bytes), count (2 bytes, big-endian), value (1 byte)   RLE record
+//	...
+//	"EOF"
+//	size (3 bytes, big-endian)   optional truncation extension of Lunar IPS
+//
+// https://zerosoft.zophar.net/ips.php
+const (
+	ipsMagic     = "PATCH"
+	ipsEOF       = "EOF"
+	ipsEOFOffset = 0x454F46 // "EOF", which cannot be the offset of a record
+	ipsMaxSize   = 1 << 24
+	ipsMaxRecord = 0xFFFF
+	ipsMinRLE    = 8 // shorter runs take less room as plain data
+)
+
+// ApplyIPS applies the IPS patch to b and returns the patched copy.
+// Records past the end of b grow the file, and the truncation extension shrinks it.
+// nolint: gomnd
+func ApplyIPS(b []byte, patch []byte) ([]byte, error) {
+	if !bytes.HasPrefix(patch, []byte(ipsMagic)) {
+		return nil, fmt.Errorf("%w: no IPS header", ErrBadPatch)
+	}
+
+	out := append([]byte{}, b...)
+	p := patch[len(ipsMagic):]
+
+	write := func(offset int, data []byte) {
+		if end := offset + len(data); end > len(out) {
+			out = append(out, make([]byte, end-len(out))...)
+		}
+
+		copy(out[offset:], data)
+	}
+
+	for {
+		if len(p) < 3 {
+			return nil, fmt.Errorf("%w: IPS patch ends without EOF", ErrBadPatch)
+		}
+
+		if string(p[:3]) == ipsEOF {
+			p = p[3:]
+
+			break
+		}
+
+		if len(p) < 5 {
+			return nil, fmt.Errorf("%w: truncated IPS record", ErrBadPatch)
+		}
+
+		offset, size := int(p[0])<<16|int(p[1])<<8|int(p[2]), int(p[3])<<8|int(p[4])
+		p = p[5:]
+
+		if size == 0 { // RLE record
+			if len(p) < 3 {
+				return nil, fmt.Errorf("%w: truncated IPS RLE record at %#06x", ErrBadPatch, offset)
+			}
+
+			write(offset, bytes.Repeat(p[2:3], int(p[0])<<8|int(p[1])))
+			p = p[3:]
+
+			continue
+		}
+
+		if len(p) < size {
+			return nil, fmt.Errorf("%w: truncated IPS record at %#06x", ErrBadPatch, offset)
+		}
+
+		write(offset, p[:size])
+		p = p[size:]
+	}
+
+	switch len(p) {
+	case 0:
+	case 3:
+		if size := int(p[0])<<16 | int(p[1])<<8 | int(p[2]); size < len(out) {
+			out = out[:size]
+		}
+	default:
+		return nil, fmt.Errorf("%w: %v bytes after the IPS EOF", ErrBadPatch, len(p))
+	}
+
+	return out, nil
+}
+
+// CreateIPS returns the IPS patch which turns original into modified.
+// Runs of at least 8 identical bytes become RLE records. If modified is shorter than original,
+// the patch uses the truncation extension.
+// nolint: gomnd
+func CreateIPS(original []byte, modified []byte) ([]byte, error) {
+	if len(modified) > ipsMaxSize {
+		return nil, fmt.Errorf("%w: IPS cannot address %v bytes", ErrPatchTooLarge, len(modified))
+	}
+
+	patch := []byte(ipsMagic)
+
+	differs := func(i int) bool {
+		return i >= len(original) || original[i] != modified[i]
+	}
+
+	for i := 0; i < len(modified); {
+		if !differs(i) {
+			i++
+
+			continue
+		}
+
+		// An offset which reads as "EOF" would end the patch, so start one byte earlier
+		if i == ipsEOFOffset {
+			i--
+		}
+
+		end := i + 1
+		for end < len(modified) && end-i < ipsMaxRecord && differs(end) {
+			end++
+		}
+
+		patch = appendIPSRecords(patch, i, modified[i:end])
+		i = end
+	}
+
+	if len(modified) < len(original) {
+		patch = append(append(patch, ipsEOF...), byte(len(modified)>>16), byte(len(modified)>>8), byte(len(modified)))
+	} else {
+		patch = append(patch, ipsEOF...)
+	}
+
+	return patch, nil
+}
+
+// appendIPSRecords appends the records which write data at offset, using RLE records for long runs.
+// nolint: gomnd
+func appendIPSRecords(patch []byte, offset int, data []byte) []byte {
+	record := func(offset int, size int) []byte {
+		return append(patch, byte(offset>>16), byte(offset>>8), byte(offset), byte(size>>8), byte(size))
+	}
+
+	for start := 0; start < len(data); {
+		// Plain data up to the next run which is worth an RLE record
+		end := start
+		for end < len(data) && runLength(data[end:]) < ipsMinRLE {
+			end++
+		}
+
+		// A record cannot start at "EOF" either, so the plain data takes one more byte
+		if end > start && end < len(data) && offset+end == ipsEOFOffset {
+			end++
+		}
+
+		if end > start {
+			patch = append(record(offset+start, end-start), data[start:end]...)
+			start = end
+
+			continue
+		}
+
+		run := runLength(data[start:])
+		if start+run < len(data) && offset+start+run == ipsEOFOffset {
+			run--
+		}
+
+		patch = append(record(offset+start, 0), byte(run>>8), byte(run), data[start])
+		start += run
+	}
+
+	return patch
+}
+
+// runLength returns the number of times the first byte of b repeats at its start.
+func runLength(b []byte) int {
+	n := 1
+	for n < len(b) && n < ipsMaxRecord && b[n] == b[0] {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,240 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// nolint: gomnd
+func modify(b []byte, offset int, data ...byte) []byte {
+	out := append([]byte{}, b...)
+	if end := offset + len(data); end > len(out) {
+		out = append(out, make([]byte, end-len(out))...)
+	}
+
+	copy(out[offset:], data)
+
+	return out
+}
+
+func TestCreateIPS(t *testing.T) {
+	t.Parallel()
+
+	original := rawRom([]byte{78, 69, 83, 26, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192)
+	large := make([]byte, ipsEOFOffset+100)
+
+	tests := []struct {
+		name     string
+		original []byte
+		modified []byte
+	}{
+		{
+			name:     "same file",
+			original: original,
+			modified: original,
+		},
+		{
+			name:     "header and prg-rom changes",
+			original: original,
+			modified: modify(modify(original, 6, 0x10), 100, 0xEA, 0xEA, 0xEA),
+		},
+		{
+			name:     "rle run",
+			original: original,
+			modified: modify(original, 1000, bytes.Repeat([]byte{0xFF}, 300)...),
+		},
+		{
+			name:     "grown file",
+			original: original,
+			modified: append(append([]byte{}, original...), bytes.Repeat([]byte{0xAB}, 16384)...),
+		},
+		{
+			name:     "truncated file",
+			original: original,
+			modified: original[:16+32768],
+		},
+		{
+			name:     "change at the eof offset",
+			original: large,
+			modified: modify(large, ipsEOFOffset, 1, 2, 3),
+		},
+		{
+			name:     "run ending at the eof offset",
+			original: large,
+			modified: modify(large, ipsEOFOffset-10, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			patch, err := CreateIPS(tt2.original, tt2.modified)
+			if err != nil {
+				t.Fatalf("CreateIPS() error = %v", err)
+			}
+
+			got, err := ApplyIPS(tt2.original, patch)
+			if err != nil {
+				t.Fatalf("ApplyIPS() error = %v", err)
+			}
+
+			if !bytes.Equal(got, tt2.modified) {
+				t.Errorf("ApplyIPS() size = %v, want %v", len(got), len(tt2.modified))
+			}
+		})
+	}
+}
+
+func TestApplyIPS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:  "record and rle record",
+			patch: "PATCH\x00\x00\x01\x00\x02\xAA\xBB\x00\x00\x05\x00\x00\x00\x03\xCCEOF",
+			want:  []byte{0, 0xAA, 0xBB, 0, 0, 0xCC, 0xCC, 0xCC},
+		},
+		{
+			name:  "truncation",
+			patch: "PATCHEOF\x00\x00\x02",
+			want:  []byte{0, 0},
+		},
+		{
+			name:    "no header",
+			patch:   "PATHC",
+			wantErr: ErrBadPatch,
+		},
+		{
+			name:    "no eof",
+			patch:   "PATCH\x00\x00\x01\x00\x02\xAA\xBB",
+			wantErr: ErrBadPatch,
+		},
+		{
+			name:    "truncated record",
+			patch:   "PATCH\x00\x00\x01\x00\x09\xAAEOF",
+			wantErr: ErrBadPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ApplyIPS(make([]byte, 4), []byte(tt2.patch))
+			if !errors.Is(err, tt2.wantErr) {
+				t.Fatalf("ApplyIPS() error = %v, want %v", err, tt2.wantErr)
+			}
+
+			if !bytes.Equal(got, tt2.want) {
+				t.Errorf("ApplyIPS() = % x, want % x", got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	original := rawRom([]byte{78, 69, 83, 26, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192)
+
+	rom, err := Decode(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		modified []byte
+		wantErr  error
+	}{
+		{
+			name:     "prg-rom change",
+			modified: modify(original, 16, 0x4C),
+		},
+		{
+			name:     "header declares 48 KiB of prg-rom for nrom",
+			modified: append(modify(original, 4, 3), make([]byte, 16384)...),
+			wantErr:  ErrPatchedRomInvalid,
+		},
+		{
+			name:     "header declares more than the file",
+			modified: modify(original, 4, 8),
+			wantErr:  ErrPatchedRomInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			patch, err := CreateIPS(original, tt2.modified)
+			if err != nil {
+				t.Fatalf("CreateIPS() error = %v", err)
+			}
+
+			patched, _, err := ApplyPatch(rom, patch)
+			if !errors.Is(err, tt2.wantErr) {
+				t.Fatalf("ApplyPatch() error = %v, want %v", err, tt2.wantErr)
+			}
+
+			if err == nil && patched.ProgramRom[0] != tt2.modified[16] {
+				t.Errorf("ApplyPatch() prg-rom[0] = %#x, want %#x", patched.ProgramRom[0], tt2.modified[16])
+			}
+		})
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	t.Parallel()
+
+	header := []byte{78, 69, 83, 26, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	original := rawRom(header, 32768, 8192)
+
+	source, err := Decode(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := Decode(modify(original, 16+32768, 0xAA, 0xBB))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []PatchFormat{PatchIPS, PatchBPS, PatchUPS} {
+		for _, headerless := range []bool{false, true} {
+			create, apply := CreatePatch, ApplyPatch
+			if headerless {
+				create, apply = CreatePatchHeaderless, ApplyPatchHeaderless
+			}
+
+			patch, err := create(format, source, target)
+			if err != nil {
+				t.Fatalf("CreatePatch(%v) error = %v", format, err)
+			}
+
+			patched, _, err := apply(source, patch)
+			if err != nil {
+				t.Fatalf("ApplyPatch(%v) error = %v", format, err)
+			}
+
+			if !bytes.Equal(patched.CharacterRom, target.CharacterRom) {
+				t.Errorf("ApplyPatch(CreatePatch(%v)) chr-rom[0:2] = % X, want AA BB, headerless %v",
+					format, patched.CharacterRom[:2], headerless)
+			}
+		}
+	}
+
+	if _, err := CreatePatch(PatchFormat(3), source, target); !errors.Is(err, ErrBadPatch) {
+		t.Errorf("CreatePatch(3) error = %v, want %v", err, ErrBadPatch)
+	}
+}
@@ -0,0 +1,118 @@
+package ines
+
+import "sort"
+
+// Names of the regions returned by Layout.
+const (
+	RegionHeader         = "Header"
+	RegionTrainer        = "Trainer"
+	RegionPRGROM         = "PRG-ROM"
+	RegionCHRROM         = "CHR-ROM"
+	RegionInstROM        = "PlayChoice INST-ROM"
+	RegionPROMData       = "PlayChoice PROM Data"
+	RegionPROMCounterOut = "PlayChoice PROM CounterOut"
+	RegionMiscROM        = "Miscellaneous ROM"
+	RegionTitle          = "Title"
+	RegionUnaccounted    = "Unaccounted" // trailing bytes which are neither a title block nor a section of the format
+	RegionGap            = "Gap"         // bytes between the end of a section and where the header places the next one
+	RegionOverlap        = "Overlap"     // bytes of a section which run into where the header places the next one
+)
+
+// Region is a part of the file of a Rom. Offset counts from the start of the header.
+type Region struct {
+	Name   string
+	Offset int
+	Size   int
+}
+
+// End returns the offset of the first byte after the region.
+func (r Region) End() int {
+	return r.Offset + r.Size
+}
+
+// layoutSection is a section of the file, with the size the header gives it and the size of its data in the Rom.
+type layoutSection struct {
+	name     string
+	size     int
+	declared int
+}
+
+// Layout returns the regions of the file of r, ordered by offset, with empty sections left out.
+// Each section starts where the header places it and is as large as its data in r,
+// so for a decoded Rom the regions cover the file end to end. If a field has been resized since,
+// the bytes it no longer fills are reported as a Gap and the bytes it runs into the next section as an Overlap.
+// Trailing data is a Title only if it has the size of a title block, and Unaccounted otherwise.
+// Bytes of Headerless which no field holds are reported as Unaccounted too.
+func (r Rom) Layout() []Region {
+	var regions []Region
+
+	offset := 0
+
+	for _, s := range r.layoutSections() {
+		if s.size == 0 && s.declared == 0 {
+			continue
+		}
+
+		regions = append(regions, Region{Name: s.name, Offset: offset, Size: s.size})
+
+		end, next := offset+s.size, offset+s.declared
+		if end < next {
+			regions = append(regions, Region{Name: RegionGap, Offset: end, Size: next - end})
+		}
+
+		if end > next {
+			regions = append(regions, Region{Name: RegionOverlap, Offset: next, Size: end - next})
+		}
+
+		offset = next
+	}
+
+	if fileSize := headerSize + len(r.Headerless); len(r.Headerless) != 0 && offset < fileSize {
+		regions = append(regions, Region{Name: RegionUnaccounted, Offset: offset, Size: fileSize - offset})
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool { return regions[i].Offset < regions[j].Offset })
+
+	return regions
+}
+
+// layoutSections returns the sections of the file of r in order. The sections whose size the header doesn't give,
+// the PROM and everything after it, are declared as large as their data.
+// nolint: gomnd
+func (r Rom) layoutSections() []layoutSection {
+	header := cleanHeader(r.Header, r.HeaderType)
+
+	trainer := 0
+	if header.HasTrainer() {
+		trainer = 512
+	}
+
+	sections := []layoutSection{
+		{RegionHeader, headerSize, headerSize},
+		{RegionTrainer, len(r.Trainer), trainer},
+		{RegionPRGROM, len(r.ProgramRom), header.PRGROMSize()},
+		{RegionCHRROM, len(r.CharacterRom), header.CHRROMSize()},
+	}
+
+	if r.HeaderType == FormatNES2 {
+		sections = append(sections, layoutSection{RegionMiscROM, len(r.MiscRom), len(r.MiscRom)})
+	} else {
+		instROM := 0
+		if hasBit(header[7], 1) {
+			instROM = pc10InstROMSize
+		}
+
+		sections = append(sections,
+			layoutSection{RegionInstROM, len(r.PlayChoiceInstRom), instROM},
+			layoutSection{RegionPROMData, len(r.PlayChoicePROMData), len(r.PlayChoicePROMData)},
+			layoutSection{RegionPROMCounterOut, len(r.PlayChoicePROMCounterOut), len(r.PlayChoicePROMCounterOut)},
+		)
+	}
+
+	trailing := RegionUnaccounted
+	if isTitleBlock(len(r.Title)) {
+		trailing = RegionTitle
+	}
+
+	return append(sections, layoutSection{trailing, len(r.Title), len(r.Title)})
+}
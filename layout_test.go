@@ -0,0 +1,166 @@
+package ines // nolint: testpackage
+
+import (
+	"reflect"
+	"testing"
+)
+
+// nolint: funlen
+func TestRom_Layout(t *testing.T) {
+	t.Parallel()
+
+	ines := []byte{78, 69, 83, 26, 1, 1, 0x04, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	pc10 := []byte{78, 69, 83, 26, 1, 1, 0, 0x02, 0, 0, 0, 0, 0, 0, 0, 0}
+	nes2 := []byte{78, 69, 83, 26, 1, 1, 0, 0x08, 0, 0, 0, 0, 0, 0, 0, 0}
+	nes2Misc := []byte{78, 69, 83, 26, 1, 1, 0, 0x08, 0, 0, 0, 0, 0, 0, 1, 0}
+
+	tests := []struct {
+		name string
+		b    []byte
+		want []Region
+	}{
+		{
+			name: "ines 1.0 with trainer and title",
+			b:    rawRom(ines, 512, 16384, 8192, 128),
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionTrainer, 16, 512},
+				{RegionPRGROM, 528, 16384},
+				{RegionCHRROM, 16912, 8192},
+				{RegionTitle, 25104, 128},
+			},
+		},
+		{
+			name: "ines 1.0 with trailing bytes",
+			b:    rawRom(ines, 512, 16384, 8192, 5),
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionTrainer, 16, 512},
+				{RegionPRGROM, 528, 16384},
+				{RegionCHRROM, 16912, 8192},
+				{RegionUnaccounted, 25104, 5},
+			},
+		},
+		{
+			name: "playchoice-10 with prom and title",
+			b:    append(rawRom(pc10, 16384, 8192, 8192, 16), append(append([]byte{}, pc10CounterOut...), rawRom(nil, 127)...)...),
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionPRGROM, 16, 16384},
+				{RegionCHRROM, 16400, 8192},
+				{RegionInstROM, 24592, 8192},
+				{RegionPROMData, 32784, 16},
+				{RegionPROMCounterOut, 32800, 16},
+				{RegionTitle, 32816, 127},
+			},
+		},
+		{
+			name: "nes 2.0 with title",
+			b:    rawRom(nes2, 16384, 8192, 127),
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionPRGROM, 16, 16384},
+				{RegionCHRROM, 16400, 8192},
+				{RegionTitle, 24592, 127},
+			},
+		},
+		{
+			name: "nes 2.0 with misc rom",
+			b:    rawRom(nes2Misc, 16384, 8192, 128),
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionPRGROM, 16, 16384},
+				{RegionCHRROM, 16400, 8192},
+				{RegionMiscROM, 24592, 128},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			got := rom.Layout()
+			if !reflect.DeepEqual(got, tt2.want) {
+				t.Errorf("Layout() = %v, want %v", got, tt2.want)
+			}
+
+			if end := got[len(got)-1].End(); end != len(tt2.b) {
+				t.Errorf("Layout() ends at %v, want %v", end, len(tt2.b))
+			}
+		})
+	}
+}
+
+func TestRom_Layout_resized(t *testing.T) {
+	t.Parallel()
+
+	header := []byte{78, 69, 83, 26, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	withTitle := rawRom(header, 32768, 8192, 128)
+
+	tests := []struct {
+		name string
+		b    []byte
+		edit func(rom *Rom)
+		want []Region
+	}{
+		{
+			name: "shrunk prg-rom leaves a gap",
+			b:    rawRom(header, 32768, 8192),
+			edit: func(rom *Rom) { rom.ProgramRom = rom.ProgramRom[:16384] },
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionPRGROM, 16, 16384},
+				{RegionGap, 16400, 16384},
+				{RegionCHRROM, 32784, 8192},
+			},
+		},
+		{
+			name: "grown chr-rom overlaps the title",
+			b:    withTitle,
+			edit: func(rom *Rom) { rom.CharacterRom = make([]byte, 8192+16) },
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionPRGROM, 16, 32768},
+				{RegionCHRROM, 32784, 8208},
+				{RegionOverlap, 40976, 16},
+				{RegionTitle, 40976, 128},
+			},
+		},
+		{
+			name: "dropped title leaves unaccounted bytes",
+			b:    withTitle,
+			edit: func(rom *Rom) { rom.Title = nil },
+			want: []Region{
+				{RegionHeader, 0, 16},
+				{RegionPRGROM, 16, 32768},
+				{RegionCHRROM, 32784, 8192},
+				{RegionUnaccounted, 40976, 128},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			tt2.edit(&rom)
+
+			if got := rom.Layout(); !reflect.DeepEqual(got, tt2.want) {
+				t.Errorf("Layout() = %v, want %v", got, tt2.want)
+			}
+		})
+	}
+}
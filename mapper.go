@@ -0,0 +1,216 @@
+package ines
+
+import (
+	"fmt"
+	"sync"
+)
+
+// AudioChip is the expansion audio chip of a mapper, which the Famicom mixes with its own sound.
+type AudioChip int
+
+const (
+	AudioChipNone AudioChip = iota
+	AudioChipVRC6
+	AudioChipVRC7
+	AudioChipMMC5
+	AudioChipNamco163
+	AudioChipSunsoft5B
+)
+
+func (a AudioChip) String() string {
+	switch a {
+	case AudioChipNone:
+		return "None"
+	case AudioChipVRC6:
+		return "Konami VRC6"
+	case AudioChipVRC7:
+		return "Konami VRC7"
+	case AudioChipMMC5:
+		return "Nintendo MMC5"
+	case AudioChipNamco163:
+		return "Namco 163"
+	case AudioChipSunsoft5B:
+		return "Sunsoft 5B"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// MapperInfo describes the hardware of a mapper, or of one of its submappers.
+// Sizes are in bytes. A bank size of 0 means that the memory is not switched.
+// The maximum sizes include the oversize extensions which emulators commonly support.
+type MapperInfo struct {
+	Mapper           int
+	SubMapper        int
+	Name             string   // the canonical name, e.g. "MMC3/TxROM"
+	Boards           []string // the names of the boards known to use it
+	PRGBankSize      int      // the smallest switchable PRG-ROM bank
+	CHRBankSize      int      // the smallest switchable CHR bank
+	MaxPRGROM        int      // 0 if unknown
+	MaxCHRROM        int      // 0 if unknown, or if the boards only have CHR-RAM, which CHRRAM tells
+	PRGRAM           int      // the PRG-RAM which iNES 1.0 leaves implicit
+	CHRRAM           int      // the CHR-RAM used when there is no CHR-ROM
+	PowerOfTwoPRG    bool     // banks are selected by masking the address, so the PRG-ROM size must be a power of two
+	MirroringControl bool     // the mapper switches the nametable mirroring
+	IRQ              bool
+	Audio            AudioChip
+	BusConflicts     bool // writes to the mapper registers conflict with the ROM data at the same address
+}
+
+// CHRRAMOnly tells whether the boards only have CHR-RAM, so that any CHR-ROM is a mistake.
+func (m MapperInfo) CHRRAMOnly() bool {
+	return m.MaxCHRROM == 0 && m.CHRRAM != 0
+}
+
+type mapperKey struct {
+	mapper    int
+	subMapper int
+}
+
+// mappers is the registry of LookupMapper and RegisterMapper.
+var mappers = struct { // nolint: gochecknoglobals
+	sync.RWMutex
+	m map[mapperKey]MapperInfo
+}{m: make(map[mapperKey]MapperInfo)}
+
+// The mappers known out of the box, from https://www.nesdev.org/wiki/Mapper.
+// nolint: gochecknoinits, gomnd, funlen
+func init() {
+	const kib = 1024
+
+	for _, info := range []MapperInfo{
+		{
+			Mapper: 0, Name: "NROM", Boards: []string{"NROM-128", "NROM-256", "HROM", "RROM"},
+			MaxPRGROM: 32 * kib, MaxCHRROM: 8 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true,
+		},
+		{
+			Mapper: 1, Name: "MMC1/SxROM",
+			Boards: []string{
+				"SAROM", "SBROM", "SCROM", "SEROM", "SFROM", "SGROM", "SHROM", "SJROM", "SKROM", "SLROM",
+				"SNROM", "SOROM", "SUROM", "SXROM",
+			},
+			PRGBankSize: 16 * kib, CHRBankSize: 4 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 128 * kib,
+			PRGRAM: 8 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true,
+		},
+		{
+			Mapper: 2, Name: "UxROM", Boards: []string{"UNROM", "UOROM"},
+			PRGBankSize: 16 * kib, MaxPRGROM: 4096 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, BusConflicts: true,
+		},
+		{
+			Mapper: 3, Name: "CNROM", Boards: []string{"CNROM"},
+			CHRBankSize: 8 * kib, MaxPRGROM: 32 * kib, MaxCHRROM: 2048 * kib, PowerOfTwoPRG: true, BusConflicts: true,
+		},
+		{
+			Mapper: 4, Name: "MMC3/TxROM",
+			Boards: []string{
+				"TBROM", "TEROM", "TFROM", "TGROM", "TKROM", "TLROM", "TL1ROM", "TLSROM", "TKSROM", "TNROM",
+				"TQROM", "TR1ROM", "TSROM", "TVROM",
+			},
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 8 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true,
+		},
+		{
+			Mapper: 4, SubMapper: 1, Name: "MMC6/HKROM", Boards: []string{"HKROM"},
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 1 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true,
+		},
+		{
+			Mapper: 5, Name: "MMC5/ExROM", Boards: []string{"EKROM", "ELROM", "ETROM", "EWROM"},
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 1024 * kib, MaxCHRROM: 1024 * kib,
+			PRGRAM: 8 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true,
+			Audio: AudioChipMMC5,
+		},
+		{
+			Mapper: 7, Name: "AxROM", Boards: []string{"AMROM", "ANROM", "AN1ROM", "AOROM"},
+			PRGBankSize: 32 * kib, MaxPRGROM: 512 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true,
+			BusConflicts: true,
+		},
+		{
+			Mapper: 9, Name: "MMC2/PxROM", Boards: []string{"PNROM", "PEEOROM"},
+			PRGBankSize: 8 * kib, CHRBankSize: 4 * kib, MaxPRGROM: 128 * kib, MaxCHRROM: 128 * kib,
+			PowerOfTwoPRG: true, MirroringControl: true,
+		},
+		{
+			Mapper: 10, Name: "MMC4/FxROM", Boards: []string{"FJROM", "FKROM"},
+			PRGBankSize: 16 * kib, CHRBankSize: 4 * kib, MaxPRGROM: 256 * kib, MaxCHRROM: 128 * kib,
+			PRGRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true,
+		},
+		{
+			Mapper: 11, Name: "Color Dreams",
+			PRGBankSize: 32 * kib, CHRBankSize: 8 * kib, MaxPRGROM: 128 * kib, MaxCHRROM: 128 * kib,
+			PowerOfTwoPRG: true, BusConflicts: true,
+		},
+		{
+			Mapper: 13, Name: "CPROM", Boards: []string{"CPROM"},
+			CHRBankSize: 4 * kib, MaxPRGROM: 32 * kib, CHRRAM: 16 * kib, PowerOfTwoPRG: true, BusConflicts: true,
+		},
+		{
+			Mapper: 19, Name: "Namco 163",
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true, Audio: AudioChipNamco163,
+		},
+		{
+			Mapper: 24, Name: "VRC6a", Boards: []string{"351951"},
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 256 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true, Audio: AudioChipVRC6,
+		},
+		{
+			Mapper: 26, Name: "VRC6b", Boards: []string{"351949A"},
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 256 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true, Audio: AudioChipVRC6,
+		},
+		{
+			Mapper: 66, Name: "GxROM", Boards: []string{"GNROM", "MHROM"},
+			PRGBankSize: 32 * kib, CHRBankSize: 8 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 128 * kib,
+			PowerOfTwoPRG: true, BusConflicts: true,
+		},
+		{
+			Mapper: 69, Name: "Sunsoft FME-7", Boards: []string{"JLROM", "JSROM", "NES-BTR"},
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true, Audio: AudioChipSunsoft5B,
+		},
+		{
+			Mapper: 71, Name: "Camerica/Codemasters", Boards: []string{"BF9093", "BF9097", "BF9096"},
+			PRGBankSize: 16 * kib, MaxPRGROM: 256 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true,
+		},
+		{
+			Mapper: 85, Name: "VRC7",
+			PRGBankSize: 8 * kib, CHRBankSize: 1 * kib, MaxPRGROM: 512 * kib, MaxCHRROM: 256 * kib,
+			PRGRAM: 8 * kib, CHRRAM: 8 * kib, PowerOfTwoPRG: true, MirroringControl: true, IRQ: true,
+			Audio: AudioChipVRC7,
+		},
+	} {
+		mappers.m[mapperKey{info.Mapper, info.SubMapper}] = info
+	}
+}
+
+// LookupMapper returns the description of the mapper and submapper from the registry.
+// A submapper which isn't registered falls back to submapper 0, which describes the mapper as a whole.
+func LookupMapper(mapper int, subMapper int) (MapperInfo, bool) {
+	mappers.RLock()
+	defer mappers.RUnlock()
+
+	if info, ok := mappers.m[mapperKey{mapper, subMapper}]; ok {
+		return info, true
+	}
+
+	info, ok := mappers.m[mapperKey{mapper, 0}]
+
+	return info, ok
+}
+
+// RegisterMapper adds info to the registry, replacing any entry of the same mapper and submapper.
+// It returns ErrUnencodable if the numbers don't fit in a NES 2.0 header.
+// nolint: gomnd
+func RegisterMapper(info MapperInfo) error {
+	if info.Mapper < 0 || info.Mapper > 0xFFF || info.SubMapper < 0 || info.SubMapper > 0x0F {
+		return fmt.Errorf("%w: mapper %v submapper %v", ErrUnencodable, info.Mapper, info.SubMapper)
+	}
+
+	mappers.Lock()
+	defer mappers.Unlock()
+
+	mappers.m[mapperKey{info.Mapper, info.SubMapper}] = info
+
+	return nil
+}
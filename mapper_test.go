@@ -0,0 +1,76 @@
+package ines // nolint: testpackage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupMapper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mapper    int
+		subMapper int
+		want      string
+		ok        bool
+	}{
+		{name: "mmc3", mapper: 4, want: "MMC3/TxROM", ok: true},
+		{name: "mmc6 submapper", mapper: 4, subMapper: 1, want: "MMC6/HKROM", ok: true},
+		{name: "unregistered submapper falls back", mapper: 4, subMapper: 9, want: "MMC3/TxROM", ok: true},
+		{name: "unregistered mapper", mapper: 4095},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			info, ok := LookupMapper(tt2.mapper, tt2.subMapper)
+			if info.Name != tt2.want || ok != tt2.ok {
+				t.Errorf("LookupMapper() = %q, %v, want %q, %v", info.Name, ok, tt2.want, tt2.ok)
+			}
+		})
+	}
+}
+
+func TestRegisterMapper(t *testing.T) {
+	t.Parallel()
+
+	info := MapperInfo{Mapper: 3840, SubMapper: 2, Name: "Homebrew", PRGBankSize: 4096, MaxPRGROM: 65536}
+	if err := RegisterMapper(info); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := LookupMapper(3840, 2); !ok || got.Name != info.Name || got.MaxPRGROM != info.MaxPRGROM {
+		t.Errorf("LookupMapper() = %+v, %v, want %+v", got, ok, info)
+	}
+
+	if _, ok := LookupMapper(3840, 0); ok {
+		t.Error("LookupMapper() found submapper 0, which wasn't registered")
+	}
+
+	if err := RegisterMapper(MapperInfo{Mapper: 4096}); !errors.Is(err, ErrUnencodable) {
+		t.Errorf("RegisterMapper() error = %v, want ErrUnencodable", err)
+	}
+}
+
+func TestValidate_unknownMapperSizes(t *testing.T) {
+	t.Parallel()
+
+	// The maximum sizes are left out, so any is fine
+	if err := RegisterMapper(MapperInfo{Mapper: 3841, Name: "Homebrew without limits"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rom, err := Decode(rawRom([]byte{78, 69, 83, 26, 8, 64, 0x10, 0x08, 0x0F, 0, 0, 0, 0, 0, 0, 0}, 131072, 524288))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, diagnostic := range Validate(rom) {
+		if diagnostic.Code == CodePRGSize || diagnostic.Code == CodeCHRSize {
+			t.Errorf("Validate() = %v, want no %v or %v", diagnostic, CodePRGSize, CodeCHRSize)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package ines
+
+import (
+	"crypto/sha1" // nolint: gosec
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrBadDatabase is returned when a ROM database cannot be parsed.
+var ErrBadDatabase = errors.New("malformed ROM database")
+
+// DBEntry holds the header values which the NES 2.0 header database gives for a game.
+// Sizes are in bytes.
+type DBEntry struct {
+	Name            string // the comment of the entry, usually the No-Intro file name
+	PRGROMSize      int
+	CHRROMSize      int
+	PRGCRC32        uint32
+	CHRCRC32        uint32
+	ROMCRC32        uint32 // of the headerless data
+	ROMSHA1         [sha1.Size]byte
+	Mapper          int
+	SubMapper       int
+	Mirroring       Mirroring
+	HasBattery      bool
+	ProgramRAM      int
+	ProgramNVRam    int
+	CharacterRAM    int
+	CharacterNVRam  int
+	ConsoleType     ConsoleType
+	CPUPPUTiming    CPUPPUTiming
+	ExpansionDevice ExpansionDevice
+	VsSystemPPU     VsPPUType
+	VsSystemType    VsSystemType
+}
+
+// Database is the NES 2.0 header database, nes20db.xml, maintained on nesdev.
+// https://forums.nesdev.org/viewtopic.php?t=19940
+type Database struct {
+	bySHA1  map[[sha1.Size]byte]DBEntry
+	byCRC32 map[[2]uint32]DBEntry // by the CRC32 of the PRG-ROM and the CHR-ROM
+}
+
+// nes20dbGame is a <game> element of nes20db.xml.
+type nes20dbGame struct {
+	Comment   string         `xml:",comment"`
+	PRGROM    nes20dbROM     `xml:"prgrom"`
+	CHRROM    nes20dbROM     `xml:"chrrom"`
+	ROM       nes20dbROM     `xml:"rom"`
+	PRGRAM    nes20dbSize    `xml:"prgram"`
+	PRGNVRAM  nes20dbSize    `xml:"prgnvram"`
+	CHRRAM    nes20dbSize    `xml:"chrram"`
+	CHRNVRAM  nes20dbSize    `xml:"chrnvram"`
+	PCB       nes20dbPCB     `xml:"pcb"`
+	Console   nes20dbConsole `xml:"console"`
+	Expansion struct {
+		Type int `xml:"type,attr"`
+	} `xml:"expansion"`
+	Vs *struct {
+		Hardware int `xml:"hardware,attr"`
+		PPU      int `xml:"ppu,attr"`
+	} `xml:"vs"`
+}
+
+type nes20dbROM struct {
+	Size  int    `xml:"size,attr"`
+	CRC32 string `xml:"crc32,attr"`
+	SHA1  string `xml:"sha1,attr"`
+}
+
+type nes20dbSize struct {
+	Size int `xml:"size,attr"`
+}
+
+type nes20dbPCB struct {
+	Mapper    int    `xml:"mapper,attr"`
+	SubMapper int    `xml:"submapper,attr"`
+	Mirroring string `xml:"mirroring,attr"`
+	Battery   int    `xml:"battery,attr"`
+}
+
+type nes20dbConsole struct {
+	Type   int `xml:"type,attr"`
+	Region int `xml:"region,attr"`
+}
+
+// LoadDatabase reads the NES 2.0 header database from the nes20db.xml file at path.
+func LoadDatabase(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database %v - Error: %w", path, err)
+	}
+	defer f.Close()
+
+	return ParseDatabase(f)
+}
+
+// ParseDatabase parses the NES 2.0 header database in the nes20db.xml format.
+func ParseDatabase(r io.Reader) (*Database, error) {
+	var db struct {
+		Games []nes20dbGame `xml:"game"`
+	}
+
+	if err := xml.NewDecoder(r).Decode(&db); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadDatabase, err)
+	}
+
+	database := &Database{
+		bySHA1:  make(map[[sha1.Size]byte]DBEntry, len(db.Games)),
+		byCRC32: make(map[[2]uint32]DBEntry, len(db.Games)),
+	}
+
+	for _, game := range db.Games {
+		entry, err := game.entry()
+		if err != nil {
+			return nil, fmt.Errorf("%w: game %q: %v", ErrBadDatabase, entry.Name, err)
+		}
+
+		database.bySHA1[entry.ROMSHA1] = entry
+		database.byCRC32[[2]uint32{entry.PRGCRC32, entry.CHRCRC32}] = entry
+	}
+
+	return database, nil
+}
+
+// nolint: gomnd
+func (g nes20dbGame) entry() (DBEntry, error) {
+	entry := DBEntry{
+		Name:            strings.TrimSpace(g.Comment),
+		PRGROMSize:      g.PRGROM.Size,
+		CHRROMSize:      g.CHRROM.Size,
+		Mapper:          g.PCB.Mapper,
+		SubMapper:       g.PCB.SubMapper,
+		HasBattery:      g.PCB.Battery != 0,
+		ProgramRAM:      g.PRGRAM.Size,
+		ProgramNVRam:    g.PRGNVRAM.Size,
+		CharacterRAM:    g.CHRRAM.Size,
+		CharacterNVRam:  g.CHRNVRAM.Size,
+		ConsoleType:     ConsoleType(g.Console.Type),
+		CPUPPUTiming:    CPUPPUTiming(g.Console.Region),
+		ExpansionDevice: ExpansionDevice(g.Expansion.Type),
+		VsSystemPPU:     VsPPUTypeUnknown,
+		VsSystemType:    VsSystemTypeUnknown,
+	}
+
+	switch g.PCB.Mirroring {
+	case "V":
+		entry.Mirroring = MirroringVertical
+	case "4":
+		entry.Mirroring = MirroringFourScreen
+	default: // "H", or mapper-controlled
+		entry.Mirroring = MirroringHorizontal
+	}
+
+	if g.Vs != nil {
+		entry.VsSystemPPU, entry.VsSystemType = VsPPUType(g.Vs.PPU), VsSystemType(g.Vs.Hardware)
+	}
+
+	for _, crc := range []struct {
+		dst *uint32
+		src string
+	}{{&entry.PRGCRC32, g.PRGROM.CRC32}, {&entry.CHRCRC32, g.CHRROM.CRC32}, {&entry.ROMCRC32, g.ROM.CRC32}} {
+		if crc.src == "" {
+			continue
+		}
+
+		v, err := strconv.ParseUint(crc.src, 16, 32)
+		if err != nil {
+			return entry, fmt.Errorf("crc32 %q: %w", crc.src, err)
+		}
+
+		*crc.dst = uint32(v)
+	}
+
+	sum, err := hex.DecodeString(g.ROM.SHA1)
+	if err != nil || len(sum) != sha1.Size {
+		return entry, fmt.Errorf("sha1 %q is not %v hex bytes", g.ROM.SHA1, sha1.Size)
+	}
+
+	copy(entry.ROMSHA1[:], sum)
+
+	return entry, nil
+}
+
+// Lookup finds the entry of rom by the SHA-1 of its ROM data, which is the trainer, PRG-ROM, CHR-ROM
+// and Miscellaneous ROM (or PlayChoice-10 data) without the header or any title block.
+// If there is no such entry, it falls back to the CRC32 of the PRG-ROM and the CHR-ROM.
+func (db *Database) Lookup(rom Rom) (DBEntry, bool) {
+	h := sha1.New() // nolint: gosec
+
+	for _, b := range [][]byte{
+		rom.Trainer, rom.ProgramRom, rom.CharacterRom, rom.MiscRom,
+		rom.PlayChoiceInstRom, rom.PlayChoicePROMData, rom.PlayChoicePROMCounterOut,
+	} {
+		_, _ = h.Write(b) // hash writers never return an error
+	}
+
+	var sum [sha1.Size]byte
+
+	copy(sum[:], h.Sum(nil))
+
+	if entry, ok := db.bySHA1[sum]; ok {
+		return entry, true
+	}
+
+	entry, ok := db.byCRC32[[2]uint32{crc32.ChecksumIEEE(rom.ProgramRom), crc32.ChecksumIEEE(rom.CharacterRom)}]
+
+	return entry, ok
+}
+
+// FixHeader rewrites the header of rom as NES 2.0 with the values of entry.
+// The ROM data is kept as it is, so entry should come from Lookup.
+func FixHeader(rom Rom, entry DBEntry) (Rom, error) {
+	fixed := rom
+	fixed.Header = cleanHeader(rom.Header, FormatArchaicINES)
+	fixed.HeaderType = FormatNES2
+	fixed.Mapper, fixed.SubMapper = entry.Mapper, entry.SubMapper
+	fixed.Mirroring = entry.Mirroring
+	fixed.HasBattery = entry.HasBattery
+	fixed.ProgramRAM, fixed.ProgramNVRam = make([]byte, entry.ProgramRAM), make([]byte, entry.ProgramNVRam)
+	fixed.CharacterRAM, fixed.CharacterNVRam = make([]byte, entry.CharacterRAM), make([]byte, entry.CharacterNVRam)
+	fixed.ConsoleType = entry.ConsoleType
+	fixed.TVSystem, fixed.CPUPPUTiming = TVSystem(entry.CPUPPUTiming), entry.CPUPPUTiming
+	fixed.ExpansionDevice = entry.ExpansionDevice
+	fixed.VsSystemPPU, fixed.VsSystemType = entry.VsSystemPPU, entry.VsSystemType
+
+	if len(rom.PlayChoiceInstRom) != 0 {
+		fixed.MiscRom = playChoiceMiscRom(rom)
+		fixed.PlayChoiceInstRom, fixed.PlayChoicePROMData, fixed.PlayChoicePROMCounterOut = nil, nil, nil
+	}
+
+	return reencode(fixed)
+}
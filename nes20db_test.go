@@ -0,0 +1,76 @@
+package ines // nolint: testpackage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDatabase_Lookup(t *testing.T) {
+	t.Parallel()
+
+	db, err := LoadDatabase("testdata/nes20db.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rom, err := Decode(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, ok := db.Lookup(rom)
+	if !ok {
+		t.Fatal("Lookup() found nothing")
+	}
+
+	if entry.Name != "thewit-demo.nes" || entry.PRGCRC32 != 0xDB00D827 || entry.CPUPPUTiming != CPUPPUTimingPAL {
+		t.Errorf("Lookup() = %+v", entry)
+	}
+
+	fixed, err := FixHeader(rom, entry)
+	if err != nil {
+		t.Fatalf("FixHeader() error = %v", err)
+	}
+
+	if fixed.HeaderType != FormatNES2 || fixed.Mirroring != MirroringVertical || !fixed.HasBattery ||
+		len(fixed.ProgramNVRam) != 8192 || fixed.TVSystem != TVSystemPAL ||
+		fixed.ExpansionDevice != ExpansionDeviceStandardControllers {
+		t.Errorf("FixHeader() header = % x", fixed.Header)
+	}
+
+	// Only the header changes
+	if h := Hash(fixed); h.Headerless != Hash(rom).Headerless {
+		t.Errorf("FixHeader() changed the ROM data")
+	}
+
+	rom.ProgramRom = make([]byte, len(rom.ProgramRom))
+	if _, ok := db.Lookup(rom); ok {
+		t.Errorf("Lookup() found a modified ROM")
+	}
+}
+
+func TestParseDatabase(t *testing.T) {
+	t.Parallel()
+
+	db, err := LoadDatabase("testdata/nes20db.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs := db.byCRC32[[2]uint32{1, 0}]
+	if vs.Mapper != 99 || vs.Mirroring != MirroringFourScreen || vs.ConsoleType != ConsoleTypeVs ||
+		vs.VsSystemPPU != VsPPURP2C03G || vs.VsSystemType != VsUnisystem || vs.CharacterRAM != 8192 {
+		t.Errorf("ParseDatabase() = %+v", vs)
+	}
+
+	_, err = ParseDatabase(strings.NewReader(`<nes20db><game><rom sha1="xyz"/></game></nes20db>`))
+	if !errors.Is(err, ErrBadDatabase) {
+		t.Errorf("ParseDatabase() error = %v, want %v", err, ErrBadDatabase)
+	}
+}
@@ -1,16 +1,25 @@
 package ines
 
 // identifyFmt activates the appropriate section format.
-// It returns nil if no format was identified.
+// It returns an error if no format was identified.
 // nolint: exhaustivestruct, varnamelen
-func identifyFmt(b []byte) Rom {
-	if hasHeader(b) {
-		if isINES2(b) {
-			return parseINES2(b)
-		}
+func identifyFmt(b []byte) (Rom, error) {
+	if !hasHeader(b) {
+		return Rom{}, ErrNoHeader
+	}
+
+	if len(b) < headerSize {
+		return Rom{}, ErrTruncatedHeader
+	}
+
+	var header Header
+
+	copy(header[:], b)
 
-		return parseINES(b)
+	format := detectFormat(header, len(b))
+	if format == FormatNES2 {
+		return parseINES2(b)
 	}
 
-	return Rom{}
+	return parseINES(b, format)
 }
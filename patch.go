@@ -0,0 +1,174 @@
+package ines
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrBadPatch is returned when a patch is malformed, or of an unknown format.
+	ErrBadPatch = errors.New("malformed patch")
+
+	// ErrPatchTooLarge is returned when the files are too large for the patch format.
+	ErrPatchTooLarge = errors.New("file too large for the patch format")
+
+	// ErrSourceMismatch is returned when a patch is applied to another file than the one it was made for.
+	ErrSourceMismatch = errors.New("input does not match the source of the patch")
+
+	// ErrTargetMismatch is returned when applying a patch does not give the file it was made to produce.
+	ErrTargetMismatch = errors.New("output does not match the target of the patch")
+
+	// ErrAlreadyPatched is returned when a patch is applied to the file it produces.
+	ErrAlreadyPatched = errors.New("input is already patched")
+
+	// ErrNotPatched is returned when a patch is reverted on the file it was made for.
+	ErrNotPatched = errors.New("input is not patched")
+
+	// ErrPatchedRomInvalid is returned when the patched ROM fails validation.
+	ErrPatchedRomInvalid = errors.New("patched ROM is invalid")
+)
+
+// PatchFormat is the format of a patch.
+type PatchFormat int
+
+const (
+	PatchIPS PatchFormat = iota // International Patching System, with the RLE and truncation extensions
+	PatchBPS                    // beat patch, which copies relocated data and checks the CRC32 of both files
+	PatchUPS                    // Universal Patching System, which XORs the data so that it can be reverted
+)
+
+func (f PatchFormat) String() string {
+	switch f {
+	case PatchIPS:
+		return "IPS"
+	case PatchBPS:
+		return "BPS"
+	case PatchUPS:
+		return "UPS"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// CreatePatch returns the patch in format which turns the file of source into the file of target,
+// as Encode returns them.
+func CreatePatch(format PatchFormat, source Rom, target Rom) ([]byte, error) {
+	return createPatch(format, source, target, false)
+}
+
+// CreatePatchHeaderless is like CreatePatch, but leaves the headers out of the patch,
+// as the patches made against headerless dumps, like the No-Intro ones, do.
+func CreatePatchHeaderless(format PatchFormat, source Rom, target Rom) ([]byte, error) {
+	return createPatch(format, source, target, true)
+}
+
+// createPatch encodes source and target and returns the patch in format between them, or between their
+// headerless data.
+func createPatch(format PatchFormat, source Rom, target Rom, headerless bool) ([]byte, error) {
+	a, err := Encode(source)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := Encode(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if headerless {
+		a, b = a[headerSize:], b[headerSize:]
+	}
+
+	switch format {
+	case PatchIPS:
+		return CreateIPS(a, b)
+	case PatchBPS:
+		return CreateBPS(a, b), nil
+	case PatchUPS:
+		return CreateUPS(a, b), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown patch format %v", ErrBadPatch, int(format))
+	}
+}
+
+// ApplyPatch applies the patch to the file of rom, as returned by Encode, and decodes the result.
+// The format of the patch, IPS, BPS or UPS, is detected from its header.
+// The patched ROM goes through Validate. If it reports errors, ApplyPatch returns ErrPatchedRomInvalid
+// along with the patched ROM and the diagnostics, so that a patch which breaks the header is caught.
+func ApplyPatch(rom Rom, patch []byte) (Rom, []Diagnostic, error) {
+	return patchRom(rom, patch, false, applyPatch)
+}
+
+// ApplyPatchHeaderless is like ApplyPatch, but applies the patch to the headerless data of rom
+// and keeps its header. Patches made against headerless dumps, like the No-Intro ones, need it.
+func ApplyPatchHeaderless(rom Rom, patch []byte) (Rom, []Diagnostic, error) {
+	return patchRom(rom, patch, true, applyPatch)
+}
+
+// RevertPatch undoes the UPS patch on the file of rom, which must be the patched one.
+// Only UPS patches can be reverted, as they XOR the data.
+func RevertPatch(rom Rom, patch []byte) (Rom, []Diagnostic, error) {
+	return patchRom(rom, patch, false, RevertUPS)
+}
+
+// RevertPatchHeaderless is like RevertPatch, for patches made against the headerless data.
+func RevertPatchHeaderless(rom Rom, patch []byte) (Rom, []Diagnostic, error) {
+	return patchRom(rom, patch, true, RevertUPS)
+}
+
+// patchRom applies the patch to the file of rom, or to its headerless data, with apply.
+func patchRom(rom Rom, patch []byte, headerless bool, apply func([]byte, []byte) ([]byte, error)) (
+	Rom, []Diagnostic, error) {
+	b, err := Encode(rom)
+	if err != nil {
+		return Rom{}, nil, err
+	}
+
+	if !headerless {
+		patched, err := apply(b, patch)
+		if err != nil {
+			return Rom{}, nil, err
+		}
+
+		return decodePatched(patched)
+	}
+
+	patched, err := apply(b[headerSize:], patch)
+	if err != nil {
+		return Rom{}, nil, err
+	}
+
+	return decodePatched(append(b[:headerSize:headerSize], patched...))
+}
+
+// applyPatch applies the patch to b, in the format given by its header.
+func applyPatch(b []byte, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, []byte(ipsMagic)):
+		return ApplyIPS(b, patch)
+	case bytes.HasPrefix(patch, []byte(bpsMagic)):
+		return ApplyBPS(b, patch)
+	case bytes.HasPrefix(patch, []byte(upsMagic)):
+		return ApplyUPS(b, patch)
+	default:
+		return nil, fmt.Errorf("%w: unknown patch format", ErrBadPatch)
+	}
+}
+
+// decodePatched decodes and validates a patched file.
+func decodePatched(b []byte) (Rom, []Diagnostic, error) {
+	rom, err := Decode(b)
+	if err != nil {
+		return Rom{}, nil, fmt.Errorf("%w: %v", ErrPatchedRomInvalid, err)
+	}
+
+	diagnostics := Validate(rom)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return rom, diagnostics, fmt.Errorf("%w: %v", ErrPatchedRomInvalid, diagnostic)
+		}
+	}
+
+	return rom, diagnostics, nil
+}
@@ -0,0 +1,104 @@
+package ines
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// The PlayChoice-10 data follows the CHR-ROM: the INST-ROM, then the output of the RP5H01 security PROM.
+// https://www.nesdev.org/wiki/PC10_ROM-Images
+const (
+	pc10InstROMSize = 8192
+	pc10PROMSize    = 16
+)
+
+// ErrBadPlayChoice10 is returned when the PlayChoice-10 data doesn't have the layout of a cartridge.
+var ErrBadPlayChoice10 = errors.New("invalid PlayChoice-10 data")
+
+// pc10CounterOut is the RP5H01 counter output while its 128 data bits are read, which toggles every 32 bits.
+var pc10CounterOut = []byte{ // nolint: gochecknoglobals
+	0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF,
+}
+
+// PlayChoice10 is the data a PlayChoice-10 cartridge has on top of the NES game.
+// Many dumps omit the PROM, in which case PROMData and PROMCounterOut are empty.
+// The INST-ROM is kept as it is found in the file: decrypting it, or checking it against the PROM data
+// the way the BIOS authenticates a cartridge, is not implemented, as no public description of it exists.
+type PlayChoice10 struct {
+	InstROM        []byte // 8 KiB of data and Z80 code for the instruction screens
+	PROMData       []byte // 16 bytes of RP5H01 PROM data output, the key the BIOS checks the cartridge with
+	PROMCounterOut []byte // 16 bytes of RP5H01 PROM counter output
+}
+
+// PlayChoice10 returns the PlayChoice-10 data of rom, which iNES 1.0 places after the CHR-ROM
+// and NES 2.0 in the Miscellaneous ROM. It returns false if rom has none.
+func (r Rom) PlayChoice10() (PlayChoice10, bool) {
+	if len(r.PlayChoiceInstRom) != 0 {
+		return PlayChoice10{
+			InstROM:        r.PlayChoiceInstRom,
+			PROMData:       r.PlayChoicePROMData,
+			PROMCounterOut: r.PlayChoicePROMCounterOut,
+		}, true
+	}
+
+	if r.HeaderType == FormatNES2 && r.ConsoleType == ConsoleTypePlayChoice && len(r.MiscRom) >= pc10InstROMSize {
+		pc10, _ := splitPlayChoice10(r.MiscRom)
+
+		return pc10, true
+	}
+
+	return PlayChoice10{}, false
+}
+
+// HasPROM tells whether the dump includes the PROM.
+func (p PlayChoice10) HasPROM() bool {
+	return len(p.PROMData) != 0 || len(p.PROMCounterOut) != 0
+}
+
+// Verify checks the sizes of the PlayChoice-10 data and, if the dump includes the PROM, that the counter
+// output is the one of the RP5H01. It does not check the INST-ROM against the PROM data, see PlayChoice10,
+// and the PROM data is specific to each game, so it can't be checked on its own either.
+// It returns ErrBadPlayChoice10 if the data isn't laid out as on a cartridge.
+func (p PlayChoice10) Verify() error {
+	if len(p.InstROM) != pc10InstROMSize {
+		return fmt.Errorf("%w: INST-ROM has %v bytes, want %v", ErrBadPlayChoice10, len(p.InstROM), pc10InstROMSize)
+	}
+
+	if !p.HasPROM() {
+		return nil
+	}
+
+	if len(p.PROMData) != pc10PROMSize || len(p.PROMCounterOut) != pc10PROMSize {
+		return fmt.Errorf("%w: PROM has %v data and %v CounterOut bytes, want %v each",
+			ErrBadPlayChoice10, len(p.PROMData), len(p.PROMCounterOut), pc10PROMSize)
+	}
+
+	if !bytes.Equal(p.PROMCounterOut, pc10CounterOut) {
+		return fmt.Errorf("%w: PROM CounterOut is % X, want % X", ErrBadPlayChoice10, p.PROMCounterOut, pc10CounterOut)
+	}
+
+	return nil
+}
+
+// splitPlayChoice10 splits b, which starts with the INST-ROM, into the PlayChoice-10 data and what follows it.
+// The PROM is only taken when there are enough bytes left for it and they aren't a title block,
+// because many dumps leave it out.
+func splitPlayChoice10(b []byte) (PlayChoice10, []byte) {
+	var pc10 PlayChoice10
+
+	pc10.InstROM, b = splitAt(b, pc10InstROMSize)
+
+	if len(b) >= 2*pc10PROMSize && !isTitleBlock(len(b)) {
+		pc10.PROMData, b = splitAt(b, pc10PROMSize)
+		pc10.PROMCounterOut, b = splitAt(b, pc10PROMSize)
+	}
+
+	return pc10, b
+}
+
+// isTitleBlock tells whether size is the one of the title block some dumps end with.
+// nolint: gomnd
+func isTitleBlock(size int) bool {
+	return size == 127 || size == 128
+}
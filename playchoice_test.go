@@ -0,0 +1,98 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPlayChoice10(t *testing.T) {
+	t.Parallel()
+
+	header := []byte{78, 69, 83, 26, 1, 1, 0, 0x02, 0, 0, 0, 0, 0, 0, 0, 0}
+	promData := bytes.Repeat([]byte{0x5A}, 16)
+	title := bytes.Repeat([]byte{'T'}, 128)
+	withPROM := append(append(rawRom(header, 16384, 8192, 8192), promData...), pc10CounterOut...)
+
+	tests := []struct {
+		name    string
+		b       []byte
+		prom    bool
+		title   int
+		wantErr error
+	}{
+		{
+			name: "inst-rom and prom",
+			b:    withPROM,
+			prom: true,
+		},
+		{
+			name:  "inst-rom, prom and title",
+			b:     append(append([]byte{}, withPROM...), title...),
+			prom:  true,
+			title: 128,
+		},
+		{
+			name: "inst-rom without prom",
+			b:    rawRom(header, 16384, 8192, 8192),
+		},
+		{
+			name:  "inst-rom without prom, and title",
+			b:     append(rawRom(header, 16384, 8192, 8192), title...),
+			title: 128,
+		},
+		{
+			name:    "counterout of another chip",
+			b:       modify(withPROM, len(withPROM)-1, 0x00),
+			prom:    true,
+			wantErr: ErrBadPlayChoice10,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			pc10, ok := rom.PlayChoice10()
+			if !ok || len(pc10.InstROM) != 8192 {
+				t.Fatalf("PlayChoice10() = %v bytes of INST-ROM, %v", len(pc10.InstROM), ok)
+			}
+
+			if pc10.HasPROM() != tt2.prom || len(rom.Title) != tt2.title {
+				t.Errorf("PlayChoice10() PROM %v and %v bytes of title, want %v and %v",
+					pc10.HasPROM(), len(rom.Title), tt2.prom, tt2.title)
+			}
+
+			if tt2.prom && !bytes.Equal(pc10.PROMData, promData) {
+				t.Errorf("PlayChoice10() PROM data = % X, want % X", pc10.PROMData, promData)
+			}
+
+			if err := pc10.Verify(); !errors.Is(err, tt2.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt2.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlayChoice10_nes2(t *testing.T) {
+	t.Parallel()
+
+	pc10 := append(append(bytes.Repeat([]byte{1}, 8192), bytes.Repeat([]byte{2}, 16)...), pc10CounterOut...)
+	b := append(rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0x0A, 0, 0, 0, 0, 0, 0, 0x01, 0}, 16384, 8192), pc10...)
+
+	rom, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := rom.PlayChoice10()
+	if !ok || !got.HasPROM() || got.Verify() != nil {
+		t.Errorf("PlayChoice10() = %v, PROM %v, verify %v", ok, got.HasPROM(), got.Verify())
+	}
+}
@@ -1,9 +1,9 @@
 package ines
 
 type Rom struct {
-	HeaderType      string
-	Headerless      []byte // Romdump without the header
-	Header          []byte // Added by a person, either iNES or iNES 2.0. Required by emulators.
+	HeaderType      Format
+	Headerless      []byte // Romdump without the header. The sections are slices of it, unless decoded with a copy
+	Header          Header // Added by a person, either iNES or iNES 2.0. Required by emulators. Kept as found in the file.
 	Trainer         []byte // Hacks and stuff
 	ProgramRom      []byte // Memory chip connected to the CPU. Contains the code.
 	CharacterRom    []byte // Memory chip connected to the PPU. Contains a fixed set of graphics tile data.
@@ -11,20 +11,64 @@ type Rom struct {
 	ProgramRAM      []byte
 	MiscRom         []byte
 	Mapper          int
-	SubMapper       int
-	ConsoleType     string
+	SubMapper       int // SubMapperUnspecified for iNES 1.0
+	ConsoleType     ConsoleType
 	Title           []byte
-	TVSystem        string
-	Mirroring       string
-	VsSystemPPU     string
-	VsSystemType    string
-	CPUPPUTiming    string
-	ExpansionDevice string
+	TVSystem        TVSystem
+	Mirroring       Mirroring
+	VsSystemPPU     VsPPUType
+	VsSystemType    VsSystemType
+	CPUPPUTiming    CPUPPUTiming
+	ExpansionDevice ExpansionDevice
 	CharacterRAM    []byte
 	CharacterNVRam  []byte
 	ProgramNVRam    []byte // EEPROM/Non-volatile Program RAM
+
+	// PlayChoice-10 data, which follows the CHR-ROM in iNES 1.0 files
+	PlayChoiceInstRom        []byte
+	PlayChoicePROMData       []byte
+	PlayChoicePROMCounterOut []byte
+}
+
+// DecodeOptions tells how to decode a file.
+type DecodeOptions struct {
+	// Copy gives the Rom its own copy of every section. Otherwise the sections are slices of the input,
+	// so writing to one writes to the input and to Headerless, which holds all the sections after the header.
+	Copy bool
 }
 
-func Decode(b []byte) Rom {
+// Decode parses b as an iNES 1.0 or NES 2.0 file, without copying it: the slices of the Rom alias b.
+// Use DecodeWith to get a Rom which doesn't share memory with b.
+// It returns ErrNoHeader or ErrTruncatedHeader if b does not hold a complete header,
+// and ErrSectionOutOfBounds if the header declares more data than b holds.
+func Decode(b []byte) (Rom, error) {
 	return identifyFmt(b)
 }
+
+// DecodeWith parses b as Decode does, and copies the sections if opts asks for it.
+func DecodeWith(b []byte, opts DecodeOptions) (Rom, error) {
+	rom, err := identifyFmt(b)
+	if err != nil || !opts.Copy {
+		return rom, err
+	}
+
+	return rom.Clone(), nil
+}
+
+// Clone returns a deep copy of r, in which every slice has its own memory.
+// The sections of the copy don't alias Headerless either, so editing one of them doesn't change the others.
+func (r Rom) Clone() Rom {
+	c := r
+
+	for _, field := range []*[]byte{
+		&c.Headerless, &c.Trainer, &c.ProgramRom, &c.CharacterRom, &c.ProgramRAM, &c.MiscRom, &c.Title,
+		&c.CharacterRAM, &c.CharacterNVRam, &c.ProgramNVRam,
+		&c.PlayChoiceInstRom, &c.PlayChoicePROMData, &c.PlayChoicePROMCounterOut,
+	} {
+		if *field != nil {
+			*field = append([]byte{}, *field...)
+		}
+	}
+
+	return c
+}
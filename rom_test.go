@@ -0,0 +1,171 @@
+package ines // nolint: testpackage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	content, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rom, err := Decode(content)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if len(rom.ProgramRom) != 32768 {
+		t.Errorf("Decode() PRG-ROM size = %v, want %v", len(rom.ProgramRom), 32768)
+	}
+
+	if len(rom.CharacterRom) != 8192 {
+		t.Errorf("Decode() CHR-ROM size = %v, want %v", len(rom.CharacterRom), 8192)
+	}
+}
+
+func TestDecode_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		b       []byte
+		wantErr error
+	}{
+		{
+			name:    "empty input",
+			b:       []byte{},
+			wantErr: ErrNoHeader,
+		},
+		{
+			name:    "wrong magic",
+			b:       []byte{78, 69, 83, 0, 2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			wantErr: ErrNoHeader,
+		},
+		{
+			name:    "15 byte file",
+			b:       []byte{78, 69, 83, 26, 2, 1, 1, 8, 0, 0, 0, 0, 0, 0, 0},
+			wantErr: ErrTruncatedHeader,
+		},
+		{
+			name:    "ines 1.0 missing prg-rom",
+			b:       []byte{78, 69, 83, 26, 2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			wantErr: ErrSectionOutOfBounds{Section: "PRG-ROM", Want: 32768, Have: 0},
+		},
+		{
+			name:    "ines 2.0 missing trainer",
+			b:       []byte{78, 69, 83, 26, 0, 0, 4, 8, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3},
+			wantErr: ErrSectionOutOfBounds{Section: "Trainer", Want: 512, Have: 3},
+		},
+		{
+			name:    "ines 2.0 larger than the file falls back to archaic ines",
+			b:       []byte{78, 69, 83, 26, 0x50, 0, 0, 8, 0, 0x0f, 0, 0, 0, 0, 0, 0},
+			wantErr: ErrSectionOutOfBounds{Section: "PRG-ROM", Want: 80 * 16384, Have: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Decode(tt2.b)
+			if err == nil {
+				t.Fatalf("Decode() error = nil, want %v", tt2.wantErr)
+			}
+
+			if !errors.Is(err, tt2.wantErr) {
+				t.Errorf("Decode() error = %v, want %v", err, tt2.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeWith_aliasing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        DecodeOptions
+		wantAliased bool
+	}{
+		{
+			name:        "zero-copy",
+			opts:        DecodeOptions{Copy: false},
+			wantAliased: true,
+		},
+		{
+			name:        "copy",
+			opts:        DecodeOptions{Copy: true},
+			wantAliased: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := rawRom([]byte{78, 69, 83, 26, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 16384, 8192, 128)
+
+			rom, err := DecodeWith(b, tt2.opts)
+			if err != nil {
+				t.Fatalf("DecodeWith() error = %v", err)
+			}
+
+			rom.CharacterRom[0] = 0xFF
+
+			if aliased := b[16+16384] == 0xFF; aliased != tt2.wantAliased {
+				t.Errorf("writing CHR-ROM changed the input = %v, want %v", aliased, tt2.wantAliased)
+			}
+
+			if aliased := rom.Headerless[16384] == 0xFF; aliased != tt2.wantAliased {
+				t.Errorf("writing CHR-ROM changed Headerless = %v, want %v", aliased, tt2.wantAliased)
+			}
+
+			b[len(b)-1] = 0xFF
+
+			if aliased := rom.Title[127] == 0xFF; aliased != tt2.wantAliased {
+				t.Errorf("writing the input changed Title = %v, want %v", aliased, tt2.wantAliased)
+			}
+		})
+	}
+}
+
+func TestRom_Clone(t *testing.T) {
+	t.Parallel()
+
+	pc10 := []byte{78, 69, 83, 26, 1, 1, 0x02, 0x02, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	rom, err := Decode(append(rawRom(pc10, 16384, 8192, 8192, 16), pc10CounterOut...))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	clone := rom.Clone()
+	if !reflect.DeepEqual(clone, rom) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, rom)
+	}
+
+	for _, field := range [][]byte{
+		clone.Headerless, clone.ProgramRom, clone.CharacterRom, clone.ProgramRAM,
+		clone.PlayChoiceInstRom, clone.PlayChoicePROMData, clone.PlayChoicePROMCounterOut,
+	} {
+		field[0]++
+	}
+
+	clone.Header[4]++
+
+	want, err := Decode(append(rawRom(pc10, 16384, 8192, 8192, 16), pc10CounterOut...))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rom, want) {
+		t.Errorf("writing the clone changed the original")
+	}
+}
@@ -0,0 +1,143 @@
+package ines
+
+import "fmt"
+
+// SubMapperUnspecified is the Rom.SubMapper of iNES 1.0 files, which have no submapper.
+// It is not submapper 0, which NES 2.0 defines as the default behavior of the mapper.
+const SubMapperUnspecified = -1
+
+// SubMapperStatus tells whether a submapper is meaningful for its mapper.
+type SubMapperStatus int
+
+const (
+	SubMapperUndefined    SubMapperStatus = iota // The mapper gives the submapper no meaning
+	SubMapperDefined                             // The submapper describes a variant of the mapper
+	SubMapperDeprecated                          // The variant is better described otherwise, see the Note
+	SubMapperNotSpecified                        // The header has no submapper, as in iNES 1.0
+)
+
+func (s SubMapperStatus) String() string {
+	switch s {
+	case SubMapperUndefined:
+		return "Undefined"
+	case SubMapperDefined:
+		return "Defined"
+	case SubMapperDeprecated:
+		return "Deprecated"
+	case SubMapperNotSpecified:
+		return "Unspecified"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// SubMapper is the variant of a mapper which a submapper number stands for.
+type SubMapper struct {
+	Mapper      int
+	SubMapper   int
+	Description string
+	Status      SubMapperStatus
+	Note        string // what replaces a deprecated submapper
+}
+
+func (s SubMapper) String() string {
+	switch s.Status {
+	case SubMapperDeprecated:
+		return fmt.Sprintf("%v (%v: %v)", s.Description, s.Status, s.Note)
+	case SubMapperDefined:
+		return fmt.Sprintf("%v: %v", s.SubMapper, s.Description)
+	default:
+		return s.Description
+	}
+}
+
+// subMappers holds the submappers which NES 2.0 defines, from https://www.nesdev.org/wiki/NES_2.0_submappers.
+// Submapper 0 of the mappers listed here is their default behavior.
+var subMappers = map[mapperKey]SubMapper{ // nolint: gochecknoglobals
+	{1, 1}: {Description: "SUROM", Status: SubMapperDeprecated, Note: "the PRG-ROM size tells it apart"},
+	{1, 2}: {Description: "SOROM", Status: SubMapperDeprecated, Note: "the PRG-RAM size tells it apart"},
+	{1, 3}: {Description: "MMC1A", Status: SubMapperDeprecated, Note: "use mapper 155"},
+	{1, 4}: {Description: "SXROM", Status: SubMapperDeprecated, Note: "the PRG-RAM size tells it apart"},
+	{1, 5}: {Description: "SEROM, SHROM, SH1ROM: 32 KiB of PRG-ROM, not switched", Status: SubMapperDefined},
+
+	{2, 0}: {Description: "UxROM, bus conflicts unspecified", Status: SubMapperDefined},
+	{2, 1}: {Description: "UxROM without bus conflicts", Status: SubMapperDefined},
+	{2, 2}: {Description: "UxROM with AND bus conflicts", Status: SubMapperDefined},
+	{3, 0}: {Description: "CNROM, bus conflicts unspecified", Status: SubMapperDefined},
+	{3, 1}: {Description: "CNROM without bus conflicts", Status: SubMapperDefined},
+	{3, 2}: {Description: "CNROM with AND bus conflicts", Status: SubMapperDefined},
+	{7, 0}: {Description: "AxROM, bus conflicts unspecified", Status: SubMapperDefined},
+	{7, 1}: {Description: "ANROM, without bus conflicts", Status: SubMapperDefined},
+	{7, 2}: {Description: "AMROM, AOROM, with AND bus conflicts", Status: SubMapperDefined},
+
+	{4, 0}: {Description: "MMC3C, Sharp IRQ behavior", Status: SubMapperDefined},
+	{4, 1}: {Description: "MMC6", Status: SubMapperDefined},
+	{4, 2}: {Description: "MMC3C without PRG-RAM", Status: SubMapperDeprecated, Note: "use submapper 0 without PRG-RAM"},
+	{4, 3}: {Description: "MC-ACC, Acclaim IRQ behavior", Status: SubMapperDefined},
+	{4, 4}: {Description: "MMC3A, NEC IRQ behavior", Status: SubMapperDefined},
+
+	{16, 0}: {
+		Description: "Bandai FCG, unspecified variant", Status: SubMapperDeprecated,
+		Note: "use submapper 4 or 5, or mapper 153 or 159",
+	},
+	{16, 4}: {Description: "Bandai FCG-1/FCG-2", Status: SubMapperDefined},
+	{16, 5}: {Description: "Bandai LZ93D50 with optional 24C02 EEPROM", Status: SubMapperDefined},
+
+	{21, 0}: {Description: "VRC4a or VRC4c", Status: SubMapperDefined},
+	{21, 1}: {Description: "VRC4a", Status: SubMapperDefined},
+	{21, 2}: {Description: "VRC4c", Status: SubMapperDefined},
+	{23, 0}: {Description: "VRC4e or VRC2b", Status: SubMapperDefined},
+	{23, 1}: {Description: "VRC4f", Status: SubMapperDefined},
+	{23, 2}: {Description: "VRC4e", Status: SubMapperDefined},
+	{23, 3}: {Description: "VRC2b", Status: SubMapperDefined},
+	{25, 0}: {Description: "VRC4b, VRC4d or VRC2c", Status: SubMapperDefined},
+	{25, 1}: {Description: "VRC4b", Status: SubMapperDefined},
+	{25, 2}: {Description: "VRC4d", Status: SubMapperDefined},
+	{25, 3}: {Description: "VRC2c", Status: SubMapperDefined},
+
+	{32, 0}: {Description: "Irem G-101, mapper-controlled mirroring", Status: SubMapperDefined},
+	{32, 1}: {Description: "Irem G-101, one-screen mirroring (Major League)", Status: SubMapperDefined},
+
+	{34, 0}: {Description: "BNROM or NINA-001, told apart by the CHR size", Status: SubMapperDefined},
+	{34, 1}: {Description: "NINA-001", Status: SubMapperDefined},
+	{34, 2}: {Description: "BNROM", Status: SubMapperDefined},
+
+	{68, 0}: {Description: "Sunsoft-4", Status: SubMapperDefined},
+	{68, 1}: {Description: "Sunsoft-4 with Sunsoft Dual Cartridge System licensing", Status: SubMapperDefined},
+
+	{71, 0}: {Description: "Camerica BF9093/BF9097, hard-wired mirroring", Status: SubMapperDefined},
+	{71, 1}: {Description: "Camerica BF9097, one-screen mirroring (Fire Hawk)", Status: SubMapperDefined},
+
+	{78, 0}: {Description: "Irem/Jaleco 74HC161/32", Status: SubMapperDeprecated, Note: "use submapper 1 or 3"},
+	{78, 1}: {Description: "Jaleco JF-16, one-screen mirroring (Cosmo Carrier)", Status: SubMapperDefined},
+	{78, 2}: {Description: "Irem IF-12, without mirroring control", Status: SubMapperDeprecated, Note: "use mapper 70"},
+	{78, 3}: {Description: "Irem IF-12, horizontal/vertical mirroring (Holy Diver)", Status: SubMapperDefined},
+
+	{210, 0}: {Description: "Namco 175 or 340", Status: SubMapperDeprecated, Note: "use submapper 1 or 2"},
+	{210, 1}: {Description: "Namco 175, hard-wired mirroring", Status: SubMapperDefined},
+	{210, 2}: {Description: "Namco 340, mapper-controlled mirroring", Status: SubMapperDefined},
+}
+
+// DescribeSubMapper returns the variant of mapper which subMapper stands for.
+// Submapper 0 is the default behavior of every mapper, and SubMapperUnspecified is reported as such.
+// The other numbers are SubMapperUndefined unless NES 2.0 defines them for the mapper.
+func DescribeSubMapper(mapper int, subMapper int) SubMapper {
+	description := SubMapper{Mapper: mapper, SubMapper: subMapper}
+
+	if sub, ok := subMappers[mapperKey{mapper, subMapper}]; ok {
+		sub.Mapper, sub.SubMapper = mapper, subMapper
+
+		return sub
+	}
+
+	switch subMapper {
+	case SubMapperUnspecified:
+		description.Description, description.Status = "Unspecified", SubMapperNotSpecified
+	case 0:
+		description.Description, description.Status = "Default", SubMapperDefined
+	default:
+		description.Description, description.Status = unknownOrUndefined, SubMapperUndefined
+	}
+
+	return description
+}
@@ -0,0 +1,60 @@
+package ines // nolint: testpackage
+
+import "testing"
+
+func TestDescribeSubMapper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mapper    int
+		subMapper int
+		want      string
+		status    SubMapperStatus
+	}{
+		{
+			name: "mmc1 serom", mapper: 1, subMapper: 5,
+			want: "SEROM, SHROM, SH1ROM: 32 KiB of PRG-ROM, not switched", status: SubMapperDefined,
+		},
+		{name: "mmc1a", mapper: 1, subMapper: 3, want: "MMC1A", status: SubMapperDeprecated},
+		{name: "mmc6", mapper: 4, subMapper: 1, want: "MMC6", status: SubMapperDefined},
+		{name: "default of a mapper without submappers", mapper: 9, want: "Default", status: SubMapperDefined},
+		{name: "undefined", mapper: 4, subMapper: 9, want: unknownOrUndefined, status: SubMapperUndefined},
+		{name: "ines 1.0", mapper: 4, subMapper: SubMapperUnspecified, want: "Unspecified", status: SubMapperNotSpecified},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := DescribeSubMapper(tt2.mapper, tt2.subMapper)
+			if got.Description != tt2.want || got.Status != tt2.status {
+				t.Errorf("DescribeSubMapper() = %v, want %v (%v)", got, tt2.want, tt2.status)
+			}
+
+			if got.Mapper != tt2.mapper || got.SubMapper != tt2.subMapper {
+				t.Errorf("DescribeSubMapper() = mapper %v submapper %v, want %v %v",
+					got.Mapper, got.SubMapper, tt2.mapper, tt2.subMapper)
+			}
+		})
+	}
+}
+
+func TestDecode_subMapper(t *testing.T) {
+	t.Parallel()
+
+	ines1, err := Decode(rawRom([]byte{78, 69, 83, 26, 2, 1, 0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nes2, err := Decode(rawRom([]byte{78, 69, 83, 26, 2, 1, 0x40, 0x08, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ines1.SubMapper != SubMapperUnspecified || nes2.SubMapper != 0 {
+		t.Errorf("Decode() submappers = %v and %v, want %v and 0", ines1.SubMapper, nes2.SubMapper, SubMapperUnspecified)
+	}
+}
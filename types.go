@@ -0,0 +1,231 @@
+package ines
+
+// The types below carry the raw value found in the header, so that they can be
+// switched on and written back. Their String method gives the descriptive text.
+
+// Mirroring is the nametable arrangement given by bits 0 and 3 of Header byte 6.
+type Mirroring int
+
+const (
+	MirroringHorizontal Mirroring = 0 // Horizontal, or mapper-controlled
+	MirroringVertical   Mirroring = 1
+	MirroringFourScreen Mirroring = 8 // Four-screen VRAM, the mirroring bit is ignored
+)
+
+func (m Mirroring) String() string {
+	switch m {
+	case MirroringHorizontal:
+		return "Horizontal or mapper-controlled"
+	case MirroringVertical:
+		return "Vertical"
+	case MirroringFourScreen:
+		return "Four-screen"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// ConsoleType is the console the ROM was made for, given by bits 0-1 of Header byte 7.
+// NES 2.0 gives the types from 3 up in the low nibble of Header byte 13.
+type ConsoleType int
+
+const (
+	ConsoleTypeNES                  ConsoleType = 0x0
+	ConsoleTypeVs                   ConsoleType = 0x1
+	ConsoleTypePlayChoice           ConsoleType = 0x2
+	ConsoleTypeDecimalFamiclone     ConsoleType = 0x3
+	ConsoleTypeEPSM                 ConsoleType = 0x4 // NES/Famicom with an EPSM module or plug-through cartridge
+	ConsoleTypeVT01                 ConsoleType = 0x5 // with red/cyan STN palette
+	ConsoleTypeVT02                 ConsoleType = 0x6
+	ConsoleTypeVT03                 ConsoleType = 0x7
+	ConsoleTypeVT09                 ConsoleType = 0x8
+	ConsoleTypeVT32                 ConsoleType = 0x9
+	ConsoleTypeVT369                ConsoleType = 0xA
+	ConsoleTypeUM6578               ConsoleType = 0xB
+	ConsoleTypeFamicomNetworkSystem ConsoleType = 0xC
+)
+
+// nolint: gomnd
+func (c ConsoleType) String() string {
+	switch c {
+	case ConsoleTypeNES:
+		return nes
+	case ConsoleTypeVs:
+		return vs
+	case ConsoleTypePlayChoice:
+		return playchoice
+	}
+
+	if c < 0 || c > 0x0F {
+		return unknownOrUndefined
+	}
+
+	return getExtendedConsoleType(uint8(c))
+}
+
+// TVSystem is the television standard the ROM was made for.
+// iNES 1.0 gives it in bit 0 of Header byte 9, NES 2.0 in bits 0-1 of Header byte 12.
+type TVSystem int
+
+const (
+	TVSystemNTSC        TVSystem = 0
+	TVSystemPAL         TVSystem = 1
+	TVSystemMultiRegion TVSystem = 2
+	TVSystemDendy       TVSystem = 3
+)
+
+func (t TVSystem) String() string {
+	switch t {
+	case TVSystemNTSC:
+		return "NTSC"
+	case TVSystemPAL:
+		return "PAL"
+	case TVSystemMultiRegion:
+		return "Multiple-region"
+	case TVSystemDendy:
+		return "Dendy"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// Regions returns the regions in which the TV system is used.
+func (t TVSystem) Regions() string {
+	regions, _ := getTvSystemAndCPUPpuTiming(int(t))
+
+	return regions
+}
+
+// CPUPPUTiming is the CPU/PPU timing given by bits 0-1 of NES 2.0 Header byte 12.
+type CPUPPUTiming int
+
+const (
+	CPUPPUTimingUnknown     CPUPPUTiming = -1 // iNES 1.0 doesn't specify it
+	CPUPPUTimingNTSC        CPUPPUTiming = 0  // RP2C02
+	CPUPPUTimingPAL         CPUPPUTiming = 1  // RP2C07
+	CPUPPUTimingMultiRegion CPUPPUTiming = 2
+	CPUPPUTimingDendy       CPUPPUTiming = 3 // UMC 6527P
+)
+
+func (c CPUPPUTiming) String() string {
+	_, msgCPU := getTvSystemAndCPUPpuTiming(int(c))
+
+	return msgCPU
+}
+
+// VsPPUType is the Vs. System PPU given by bits 0-3 of NES 2.0 Header byte 13.
+type VsPPUType int
+
+const (
+	VsPPUTypeUnknown VsPPUType = -1 // Not a Vs. System, or iNES 1.0
+	VsPPURP2C03B     VsPPUType = 0
+	VsPPURP2C03G     VsPPUType = 1
+	VsPPURP2C040001  VsPPUType = 2
+	VsPPURP2C040002  VsPPUType = 3
+	VsPPURP2C040003  VsPPUType = 4
+	VsPPURP2C040004  VsPPUType = 5
+	VsPPURC2C03B     VsPPUType = 6
+	VsPPURC2C03C     VsPPUType = 7
+	VsPPURC2C0501    VsPPUType = 8
+	VsPPURC2C0502    VsPPUType = 9
+	VsPPURC2C0503    VsPPUType = 10
+	VsPPURC2C0504    VsPPUType = 11
+	VsPPURC2C0505    VsPPUType = 12
+)
+
+func (v VsPPUType) String() string {
+	if v < 0 || v > 0x0F {
+		return unknownOrUndefined
+	}
+
+	return getVsPPUType(uint8(v))
+}
+
+// VsSystemType is the Vs. System hardware type given by bits 4-7 of NES 2.0 Header byte 13.
+type VsSystemType int
+
+const (
+	VsSystemTypeUnknown            VsSystemType = -1 // Not a Vs. System, or iNES 1.0
+	VsUnisystem                    VsSystemType = 0
+	VsUnisystemRBIBaseball         VsSystemType = 1
+	VsUnisystemTKOBoxing           VsSystemType = 2
+	VsUnisystemSuperXevious        VsSystemType = 3
+	VsUnisystemIceClimberJapan     VsSystemType = 4
+	VsDualSystem                   VsSystemType = 5
+	VsDualSystemRaidOnBungelingBay VsSystemType = 6
+)
+
+func (v VsSystemType) String() string {
+	if v < 0 || v > 0x0F {
+		return unknownOrUndefined
+	}
+
+	return getVsSystemType(uint8(v))
+}
+
+// ExpansionDevice is the default expansion device given by bits 0-5 of NES 2.0 Header byte 15.
+type ExpansionDevice int
+
+const (
+	ExpansionDeviceUnspecified ExpansionDevice = iota
+	ExpansionDeviceStandardControllers
+	ExpansionDeviceFourScore
+	ExpansionDeviceFamicomFourPlayersAdapter
+	ExpansionDeviceVsSystem
+	ExpansionDeviceVsSystemReversed
+	ExpansionDeviceVsPinball
+	ExpansionDeviceVsZapper
+	ExpansionDeviceZapper
+	ExpansionDeviceTwoZappers
+	ExpansionDeviceBandaiHyperShot
+	ExpansionDevicePowerPadSideA
+	ExpansionDevicePowerPadSideB
+	ExpansionDeviceFamilyTrainerSideA
+	ExpansionDeviceFamilyTrainerSideB
+	ExpansionDeviceArkanoidVausNES
+	ExpansionDeviceArkanoidVausFamicom
+	ExpansionDeviceTwoVausDataRecorder
+	ExpansionDeviceKonamiHyperShot
+	ExpansionDeviceCoconutsPachinko
+	ExpansionDevicePunchingBag
+	ExpansionDeviceJissenMahjong
+	ExpansionDevicePartyTap
+	ExpansionDeviceOekaKidsTablet
+	ExpansionDeviceBarcodeBattler
+	ExpansionDeviceMiraclePiano
+	ExpansionDevicePokkunMoguraa
+	ExpansionDeviceTopRider
+	ExpansionDeviceDoubleFisted
+	ExpansionDeviceFamicom3DSystem
+	ExpansionDeviceDoremikkoKeyboard
+	ExpansionDeviceROBGyroSet
+	ExpansionDeviceFamicomDataRecorder
+	ExpansionDeviceASCIITurboFile
+	ExpansionDeviceIGSStorageBattleBox
+	ExpansionDeviceFamilyBASICKeyboard
+	ExpansionDevicePEC586Keyboard
+	ExpansionDeviceBit79Keyboard
+	ExpansionDeviceSuborKeyboard
+	ExpansionDeviceSuborKeyboardMouse3x8
+	ExpansionDeviceSuborKeyboardMouse24
+	ExpansionDeviceSNESMouse
+	ExpansionDeviceMulticart
+	ExpansionDeviceTwoSNESControllers
+	ExpansionDeviceRacerMateBicycle
+	ExpansionDeviceUForce
+	ExpansionDeviceROBStackUp
+	ExpansionDeviceCityPatrolmanLightgun
+	ExpansionDeviceSharpC1Cassette
+	ExpansionDeviceSwappedController
+	ExpansionDeviceSudokuPad
+	ExpansionDeviceABLPinball
+	ExpansionDeviceGoldenNuggetCasino
+)
+
+func (e ExpansionDevice) String() string {
+	if e < 0 || e > 0x3F {
+		return unknownOrUndefined
+	}
+
+	return getDefaultExpansionDevice(uint8(e))
+}
@@ -0,0 +1,294 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestDecode_enums(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		header          []byte
+		wantMirroring   Mirroring
+		wantConsoleType ConsoleType
+		wantTVSystem    TVSystem
+		wantTiming      CPUPPUTiming
+		wantDevice      ExpansionDevice
+	}{
+		{
+			name:            "ines 1.0 four-screen pal",
+			header:          []byte{78, 69, 83, 26, 0, 0, 0x08, 0, 0, 1, 0, 0, 0, 0, 0, 0},
+			wantMirroring:   MirroringFourScreen,
+			wantConsoleType: ConsoleTypeNES,
+			wantTVSystem:    TVSystemPAL,
+			wantTiming:      CPUPPUTimingUnknown,
+			wantDevice:      ExpansionDeviceUnspecified,
+		},
+		{
+			name:            "ines 2.0 four-screen dendy vs. system with zapper",
+			header:          []byte{78, 69, 83, 26, 0, 0, 0x09, 0x09, 0, 0, 0, 0, 3, 0, 0, 7},
+			wantMirroring:   MirroringFourScreen,
+			wantConsoleType: ConsoleTypeVs,
+			wantTVSystem:    TVSystemDendy,
+			wantTiming:      CPUPPUTimingDendy,
+			wantDevice:      ExpansionDeviceVsZapper,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.header)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			got := []fmt.Stringer{rom.Mirroring, rom.ConsoleType, rom.TVSystem, rom.CPUPPUTiming, rom.ExpansionDevice}
+			want := []fmt.Stringer{tt2.wantMirroring, tt2.wantConsoleType, tt2.wantTVSystem, tt2.wantTiming, tt2.wantDevice}
+
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("Decode() %T = %v, want %v", got[i], got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExpansionDevice_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		device ExpansionDevice
+		want   string
+	}{
+		{device: ExpansionDeviceUnspecified, want: "Unspecified"},
+		{device: ExpansionDeviceZapper, want: "Zapper ($4017)"},
+		{device: ExpansionDeviceGoldenNuggetCasino, want: "Golden Nugget Casino extra buttons"},
+		{device: 0x3F, want: unknownOrUndefined},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.want, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt2.device.String(); got != tt2.want {
+				t.Errorf("String() = %v, want %v", got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestConsoleType_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		consoleType ConsoleType
+		want        string
+	}{
+		{
+			consoleType: ConsoleTypeDecimalFamiclone,
+			want:        "Regular Famiclone, but with CPU that supports Decimal Mode (e.g. Bit Corporation Creator)",
+		},
+		{consoleType: ConsoleTypeEPSM, want: "Regular NES/Famicom with EPSM module or plug-through cartridge"},
+		{consoleType: ConsoleTypeFamicomNetworkSystem, want: "Famicom Network System"},
+		{consoleType: 0x0F, want: unknownOrUndefined},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.want, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt2.consoleType.String(); got != tt2.want {
+				t.Errorf("String() = %v, want %v", got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestVsSystem_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "ppu", got: VsPPURP2C040004.String(), want: "RP2C04-0004"},
+		{name: "unknown ppu", got: VsPPUTypeUnknown.String(), want: unknownOrUndefined},
+		{name: "undefined ppu", got: VsPPUType(0x0D).String(), want: unknownOrUndefined},
+		{name: "hardware", got: VsDualSystem.String(), want: "Vs. Dual System (normal)"},
+		{name: "unknown hardware", got: VsSystemTypeUnknown.String(), want: unknownOrUndefined},
+		{name: "undefined hardware", got: VsSystemType(0x0F).String(), want: unknownOrUndefined},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt2.got != tt2.want {
+				t.Errorf("String() = %v, want %v", tt2.got, tt2.want)
+			}
+		})
+	}
+}
+
+// The headers are laid out as in the dumps of the cartridges, with the sizes and byte 13 of their NES 2.0 DB entries.
+// nolint: funlen
+func TestDecode_consoleType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  []byte
+		sizes   []int
+		want    ConsoleType
+		wantPPU VsPPUType
+		wantVs  VsSystemType
+		wantStr string
+	}{
+		{
+			name:    "ines 1.0 vs. super mario bros.",
+			header:  []byte{78, 69, 83, 26, 0x02, 0x02, 0x38, 0x61, 0, 0, 0, 0, 0, 0, 0, 0},
+			sizes:   []int{32768, 16384},
+			want:    ConsoleTypeVs,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: vs,
+		},
+		{
+			name:    "vs. super mario bros.",
+			header:  []byte{78, 69, 83, 26, 0x02, 0x02, 0x38, 0x69, 0, 0, 0, 0, 0, 0x05, 0, 0x04},
+			sizes:   []int{32768, 16384},
+			want:    ConsoleTypeVs,
+			wantPPU: VsPPURP2C040004,
+			wantVs:  VsUnisystem,
+			wantStr: vs,
+		},
+		{
+			name:    "vs. castlevania",
+			header:  []byte{78, 69, 83, 26, 0x08, 0x00, 0x21, 0x09, 0, 0, 0, 0x07, 0, 0x03, 0, 0x04},
+			sizes:   []int{131072},
+			want:    ConsoleTypeVs,
+			wantPPU: VsPPURP2C040002,
+			wantVs:  VsUnisystem,
+			wantStr: vs,
+		},
+		{
+			name:    "vs. raid on bungeling bay, both halves of the dual system",
+			header:  []byte{78, 69, 83, 26, 0x04, 0x02, 0x38, 0x69, 0, 0, 0, 0, 0, 0x60, 0, 0x04},
+			sizes:   []int{65536, 16384},
+			want:    ConsoleTypeVs,
+			wantPPU: VsPPURP2C03B,
+			wantVs:  VsDualSystemRaidOnBungelingBay,
+			wantStr: vs,
+		},
+		{
+			name:    "ines 1.0 playchoice-10 super mario bros.",
+			header:  []byte{78, 69, 83, 26, 0x02, 0x01, 0x01, 0x02, 0, 0, 0, 0, 0, 0, 0, 0},
+			sizes:   []int{32768, 8192, 8192, 16, 16},
+			want:    ConsoleTypePlayChoice,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: playchoice,
+		},
+		{
+			name:    "playchoice-10 super mario bros.",
+			header:  []byte{78, 69, 83, 26, 0x02, 0x01, 0x01, 0x0A, 0, 0, 0, 0, 0, 0, 0x01, 0x01},
+			sizes:   []int{32768, 8192, 8192 + 32},
+			want:    ConsoleTypePlayChoice,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: playchoice,
+		},
+		{
+			name:    "vt02 onebus multicart",
+			header:  []byte{78, 69, 83, 26, 0x20, 0x40, 0x01, 0x0B, 0x01, 0, 0, 0, 0, 0x06, 0, 0x01},
+			sizes:   []int{524288, 524288},
+			want:    ConsoleTypeVT02,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: "V.R. Technology VT02",
+		},
+		{
+			name:    "vt03 onebus multicart with the 4bpp tiles in prg-rom",
+			header:  []byte{78, 69, 83, 26, 0x80, 0x00, 0x01, 0x0B, 0x01, 0, 0, 0x07, 0, 0x07, 0, 0x01},
+			sizes:   []int{2097152},
+			want:    ConsoleTypeVT03,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: "V.R. Technology VT03",
+		},
+		{
+			name:    "vt369 plug-and-play",
+			header:  []byte{78, 69, 83, 26, 0x00, 0x00, 0x01, 0x0B, 0x01, 0x01, 0, 0x07, 0, 0x0A, 0, 0x01},
+			sizes:   []int{4194304},
+			want:    ConsoleTypeVT369,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: "V.R. Technology VT369",
+		},
+		{
+			name:    "vs. super mario bros. as extended console type 1 keeps bytes 7 and 13",
+			header:  []byte{78, 69, 83, 26, 0x02, 0x02, 0x38, 0x6B, 0, 0, 0, 0, 0, 0x01, 0, 0x04},
+			sizes:   []int{32768, 16384},
+			want:    ConsoleTypeVs,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: vs,
+		},
+		{
+			name:    "extended console type 0 keeps bytes 7 and 13",
+			header:  []byte{0x4E, 0x45, 0x53, 0x1A, 0x02, 0x01, 0x30, 0x2B, 0x30, 0xFF, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30},
+			sizes:   []int{5, 3}, // in exponent-multiplier notation
+			want:    ConsoleTypeNES,
+			wantPPU: VsPPUTypeUnknown,
+			wantVs:  VsSystemTypeUnknown,
+			wantStr: nes,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := rawRom(tt2.header, tt2.sizes...)
+
+			rom, err := Decode(b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if len(rom.ProgramRom) != tt2.sizes[0] {
+				t.Errorf("Decode() PRG-ROM size = %v, want %v", len(rom.ProgramRom), tt2.sizes[0])
+			}
+
+			if rom.ConsoleType != tt2.want || rom.ConsoleType.String() != tt2.wantStr {
+				t.Errorf("Decode() ConsoleType = %v (%q), want %v (%q)",
+					int(rom.ConsoleType), rom.ConsoleType, int(tt2.want), tt2.wantStr)
+			}
+
+			if rom.VsSystemPPU != tt2.wantPPU || rom.VsSystemType != tt2.wantVs {
+				t.Errorf("Decode() Vs. System = %v, %v, want %v, %v", rom.VsSystemPPU, rom.VsSystemType, tt2.wantPPU, tt2.wantVs)
+			}
+
+			encoded, err := Encode(rom)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if !bytes.Equal(encoded, b) {
+				t.Errorf("Encode() header = % X, want % X", encoded[:16], tt2.header)
+			}
+		})
+	}
+}
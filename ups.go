@@ -0,0 +1,146 @@
+package ines
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// UPS patches XOR the input with blocks of data, which makes them work both ways:
+//
+//	"UPS1"
+//	input size, output size (varints)
+//	relative offset (varint), XOR data terminated by a 0 byte, which also takes a position
+//	...
+//	input CRC32, output CRC32, patch CRC32 (4 bytes each, little-endian)
+//
+// http://individual.utoronto.ca/dmeunier/ups-spec.pdf
+const (
+	upsMagic      = "UPS1"
+	upsFooterSize = 12
+	upsMaxSize    = 1 << 30 // far above any NES file, to refuse allocating what a corrupted size asks for
+)
+
+// ApplyUPS applies the UPS patch to b, which must be the input the patch was made for.
+// It returns ErrAlreadyPatched if b is the output of the patch, and ErrSourceMismatch if it is neither.
+func ApplyUPS(b []byte, patch []byte) ([]byte, error) {
+	return xorUPS(b, patch, false)
+}
+
+// RevertUPS undoes the UPS patch on b, which must be the output of the patch, and returns the input.
+// It returns ErrNotPatched if b is the input of the patch, and ErrSourceMismatch if it is neither.
+func RevertUPS(b []byte, patch []byte) ([]byte, error) {
+	return xorUPS(b, patch, true)
+}
+
+// nolint: gomnd, cyclop
+func xorUPS(b []byte, patch []byte, revert bool) ([]byte, error) {
+	if !bytes.HasPrefix(patch, []byte(upsMagic)) || len(patch) < len(upsMagic)+upsFooterSize {
+		return nil, fmt.Errorf("%w: no UPS header", ErrBadPatch)
+	}
+
+	footer := patch[len(patch)-upsFooterSize:]
+	inputCRC, outputCRC := binary.LittleEndian.Uint32(footer), binary.LittleEndian.Uint32(footer[4:])
+
+	if crc, want := crc32.ChecksumIEEE(patch[:len(patch)-4]), binary.LittleEndian.Uint32(footer[8:]); crc != want {
+		return nil, fmt.Errorf("%w: UPS patch checksum is %08X, want %08X", ErrBadPatch, crc, want)
+	}
+
+	r := &varintReader{b: patch[len(upsMagic) : len(patch)-upsFooterSize]}
+	inputSize, outputSize := r.read(), r.read()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	from, to := inputCRC, outputCRC
+	if revert {
+		from, to = to, from
+		inputSize, outputSize = outputSize, inputSize
+	}
+
+	switch crc := crc32.ChecksumIEEE(b); {
+	case crc == from:
+	case crc == to && revert:
+		return nil, fmt.Errorf("%w: CRC32 %08X matches the input of the patch", ErrNotPatched, crc)
+	case crc == to:
+		return nil, fmt.Errorf("%w: CRC32 %08X matches the output of the patch", ErrAlreadyPatched, crc)
+	default:
+		return nil, fmt.Errorf("%w: CRC32 is %08X, want input %08X or output %08X",
+			ErrSourceMismatch, crc, inputCRC, outputCRC)
+	}
+
+	if inputSize != uint64(len(b)) {
+		return nil, fmt.Errorf("%w: input is %v bytes, want %v", ErrSourceMismatch, len(b), inputSize)
+	}
+
+	if outputSize > upsMaxSize {
+		return nil, fmt.Errorf("%w: UPS output of %v bytes", ErrPatchTooLarge, outputSize)
+	}
+
+	out := make([]byte, outputSize)
+	copy(out, b)
+
+	for pos := uint64(0); len(r.b) != 0; pos++ { // the terminator takes a position too
+		if pos += r.read(); r.err != nil {
+			return nil, r.err
+		}
+
+		for ; len(r.b) != 0 && r.b[0] != 0; pos++ {
+			if pos < outputSize {
+				out[pos] ^= r.b[0]
+			}
+
+			r.b = r.b[1:]
+		}
+
+		if len(r.b) == 0 {
+			return nil, fmt.Errorf("%w: UPS block without terminator", ErrBadPatch)
+		}
+
+		r.b = r.b[1:]
+	}
+
+	if crc := crc32.ChecksumIEEE(out); crc != to {
+		return nil, fmt.Errorf("%w: CRC32 is %08X, want %08X", ErrTargetMismatch, crc, to)
+	}
+
+	return out, nil
+}
+
+// CreateUPS returns the UPS patch which turns input into output, and output back into input.
+func CreateUPS(input []byte, output []byte) []byte {
+	patch := appendVarint([]byte(upsMagic), uint64(len(input)))
+	patch = appendVarint(patch, uint64(len(output)))
+
+	at := func(b []byte, i int) byte {
+		if i < len(b) {
+			return b[i]
+		}
+
+		return 0
+	}
+
+	size := len(input)
+	if len(output) > size {
+		size = len(output)
+	}
+
+	for i, last := 0, 0; i < size; i++ {
+		if at(input, i) == at(output, i) {
+			continue
+		}
+
+		patch = appendVarint(patch, uint64(i-last))
+
+		for ; i < size && at(input, i) != at(output, i); i++ {
+			patch = append(patch, at(input, i)^at(output, i))
+		}
+
+		patch = append(patch, 0)
+		last = i + 1
+	}
+
+	return appendFooter(patch, input, output)
+}
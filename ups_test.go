@@ -0,0 +1,120 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCreateUPS(t *testing.T) {
+	t.Parallel()
+
+	demo, err := Read("testdata/thewit-demo.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		input  []byte
+		output []byte
+	}{
+		{
+			name:   "few changes",
+			input:  demo,
+			output: modify(modify(demo, 6, 0x01), 0x200, 0xEA, 0xEA),
+		},
+		{
+			name:   "grown",
+			input:  demo,
+			output: append(append([]byte{}, demo...), bytes.Repeat([]byte{0xAB}, 128)...),
+		},
+		{
+			name:   "truncated",
+			input:  demo,
+			output: demo[:16+32768],
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			patch := CreateUPS(tt2.input, tt2.output)
+
+			got, err := ApplyUPS(tt2.input, patch)
+			if err != nil {
+				t.Fatalf("ApplyUPS() error = %v", err)
+			}
+
+			if !bytes.Equal(got, tt2.output) {
+				t.Errorf("ApplyUPS() size = %v, want %v", len(got), len(tt2.output))
+			}
+
+			reverted, err := RevertUPS(got, patch)
+			if err != nil {
+				t.Fatalf("RevertUPS() error = %v", err)
+			}
+
+			if !bytes.Equal(reverted, tt2.input) {
+				t.Errorf("RevertUPS() size = %v, want %v", len(reverted), len(tt2.input))
+			}
+		})
+	}
+}
+
+func TestApplyUPS_errors(t *testing.T) {
+	t.Parallel()
+
+	input, output := []byte("original file"), []byte("modified file")
+	patch := CreateUPS(input, output)
+
+	tests := []struct {
+		name    string
+		apply   func([]byte, []byte) ([]byte, error)
+		b       []byte
+		patch   []byte
+		wantErr error
+	}{
+		{
+			name:    "apply to the output",
+			apply:   ApplyUPS,
+			b:       output,
+			patch:   patch,
+			wantErr: ErrAlreadyPatched,
+		},
+		{
+			name:    "revert the input",
+			apply:   RevertUPS,
+			b:       input,
+			patch:   patch,
+			wantErr: ErrNotPatched,
+		},
+		{
+			name:    "apply to another file",
+			apply:   ApplyUPS,
+			b:       []byte("another file!"),
+			patch:   patch,
+			wantErr: ErrSourceMismatch,
+		},
+		{
+			name:    "corrupted patch",
+			apply:   ApplyUPS,
+			b:       input,
+			patch:   append(append([]byte{}, patch[:len(patch)-1]...), patch[len(patch)-1]+1),
+			wantErr: ErrBadPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := tt2.apply(tt2.b, tt2.patch); !errors.Is(err, tt2.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt2.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package ines
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Severity tells how bad a Diagnostic is.
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota // Harmless, but worth knowing about
+	SeverityWarning                 // Likely a mistake, which emulators usually work around
+	SeverityError                   // The header contradicts itself or the data, emulators may fail to run the ROM
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return unknownOrUndefined
+	}
+}
+
+// The codes of the diagnostics returned by Validate.
+const (
+	CodeTrailingData        = "trailing-data"         // the file is larger than the header declares
+	CodeReservedBits        = "reserved-bits"         // reserved header bits are set
+	CodeUnreliableBytes     = "unreliable-bytes"      // bytes the format gives no meaning to are set
+	CodePRGSize             = "prg-size"              // the PRG-ROM size doesn't suit the mapper
+	CodeCHRSize             = "chr-size"              // the CHR-ROM size doesn't suit the mapper
+	CodeSubMapper           = "submapper"             // the submapper is undefined or deprecated for the mapper
+	CodePlayChoice10        = "playchoice-10"         // the PlayChoice-10 data is incomplete or malformed
+	CodeBatteryWithoutNVRAM = "battery-without-nvram" // the battery bit is set, but there is no PRG-NVRAM
+	CodeNoCHRMemory         = "no-chr-memory"         // there is neither CHR-ROM nor CHR-RAM
+	CodeConsoleType         = "console-type"          // the console type bits contradict byte 13
+)
+
+// Diagnostic is a problem found by Validate.
+// Offset is the position in the file of the byte the problem is about.
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+	Offset   int
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%#04x: %v: %v: %v", d.Offset, d.Severity, d.Code, d.Message)
+}
+
+// Validate checks rom for inconsistencies between its header and its data, and returns what it found
+// in the order of the file.
+// The header is checked as found in the file, so Validate is meant for decoded ROMs.
+// nolint: gomnd
+func Validate(rom Rom) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	report := func(severity Severity, code string, offset int, format string, a ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: severity,
+			Code:     code,
+			Message:  fmt.Sprintf(format, a...),
+			Offset:   offset,
+		})
+	}
+
+	header := rom.Header
+
+	// The leftover after the sections the header declares
+	if len(rom.Title) != 0 {
+		severity := SeverityWarning
+		if isTitleBlock(len(rom.Title)) {
+			severity = SeverityInfo // most likely a title block
+		}
+
+		report(severity, CodeTrailingData, headerSize+len(rom.Headerless)-len(rom.Title),
+			"file has %v bytes more than the header declares", len(rom.Title))
+	}
+
+	switch rom.HeaderType {
+	case FormatNES2:
+		validateNES2(header, report)
+	case FormatINES:
+		reserved(header, 9, 0b11111110, report)
+		reserved(header, 10, 0b11001100, report)
+
+		if hasBit(header[7], 0) && hasBit(header[7], 1) {
+			report(SeverityWarning, CodeConsoleType, 7, "both the Vs. System and the PlayChoice-10 bits are set")
+		}
+	default:
+		for i := rom.HeaderType.definedBytes(); i < headerSize; i++ {
+			if header[i] != 0 {
+				report(SeverityWarning, CodeUnreliableBytes, i,
+					"%v doesn't define byte %v, which is %#02x, clean the header", rom.HeaderType, i, header[i])
+
+				break
+			}
+		}
+	}
+
+	if pc10, ok := rom.PlayChoice10(); ok {
+		offset := headerSize + len(rom.Trainer) + len(rom.ProgramRom) + len(rom.CharacterRom)
+
+		if err := pc10.Verify(); err != nil {
+			report(SeverityWarning, CodePlayChoice10, offset, "%v", err)
+		} else if !pc10.HasPROM() {
+			report(SeverityInfo, CodePlayChoice10, offset+pc10InstROMSize, "the dump has no RP5H01 PROM")
+		}
+	}
+
+	switch sub := DescribeSubMapper(rom.Mapper, rom.SubMapper); sub.Status {
+	case SubMapperUndefined:
+		report(SeverityWarning, CodeSubMapper, 8, "submapper %v is undefined for mapper %v", rom.SubMapper, rom.Mapper)
+	case SubMapperDeprecated:
+		report(SeverityInfo, CodeSubMapper, 8, "submapper %v of mapper %v (%v) is deprecated, %v",
+			rom.SubMapper, rom.Mapper, sub.Description, sub.Note)
+	}
+
+	if info, ok := LookupMapper(rom.Mapper, rom.SubMapper); ok {
+		validateMapper(rom, info, report)
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Offset < diagnostics[j].Offset })
+
+	return diagnostics
+}
+
+// validateMapper checks the PRG-ROM and CHR-ROM sizes against what the mapper can address.
+// nolint: gomnd
+func validateMapper(rom Rom, info MapperInfo, report func(Severity, string, int, string, ...interface{})) {
+	switch prg := len(rom.ProgramRom); {
+	case info.PowerOfTwoPRG && prg != 0 && bits.OnesCount(uint(prg)) != 1:
+		report(SeverityError, CodePRGSize, 4, "%v needs a power of two PRG-ROM size, have %v bytes", info.Name, prg)
+	case info.MaxPRGROM != 0 && prg > info.MaxPRGROM:
+		report(SeverityWarning, CodePRGSize, 4, "%v addresses at most %v bytes of PRG-ROM, have %v",
+			info.Name, info.MaxPRGROM, prg)
+	}
+
+	switch chr := len(rom.CharacterRom); {
+	case info.CHRRAMOnly() && chr != 0:
+		report(SeverityWarning, CodeCHRSize, 5, "%v only has CHR-RAM, have %v bytes of CHR-ROM", info.Name, chr)
+	case info.MaxCHRROM != 0 && chr > info.MaxCHRROM:
+		report(SeverityWarning, CodeCHRSize, 5, "%v addresses at most %v bytes of CHR-ROM, have %v",
+			info.Name, info.MaxCHRROM, chr)
+	}
+}
+
+// validateNES2 checks the bytes which only NES 2.0 defines.
+// nolint: gomnd
+func validateNES2(header Header, report func(Severity, string, int, string, ...interface{})) {
+	reserved(header, 12, 0b11111100, report)
+	reserved(header, 14, 0b11111100, report)
+	reserved(header, 15, 0b11000000, report)
+
+	if header.HasBattery() && header.PRGNVRAMShift() == 0 {
+		report(SeverityWarning, CodeBatteryWithoutNVRAM, 10, "battery is set, but the PRG-NVRAM shift count is zero")
+	}
+
+	if header.CHRROMSize() == 0 && header.CHRRAMShift() == 0 && header.CHRNVRAMShift() == 0 {
+		report(SeverityError, CodeNoCHRMemory, 11, "there is no CHR-ROM and no CHR-RAM is declared")
+	}
+
+	switch consoleType := header[7] & 0b00000011; {
+	case consoleType == 3:
+		reserved(header, 13, 0b11110000, report)
+
+		if extended := readLowNibbleByte(header[13]); extended <= 2 {
+			report(SeverityError, CodeConsoleType, 13,
+				"extended console type %v should be given by the bits 0-1 of byte 7", extended)
+		}
+	case consoleType != 1 && header[13] != 0:
+		report(SeverityWarning, CodeConsoleType, 13,
+			"byte 13 is %#02x, but it is only used by the Vs. System and the extended console types", header[13])
+	}
+}
+
+// reserved reports the bits of Header byte i which are set in mask.
+func reserved(header Header, i int, mask byte, report func(Severity, string, int, string, ...interface{})) {
+	if set := header[i] & mask; set != 0 {
+		report(SeverityWarning, CodeReservedBits, i, "reserved bits %#08b of byte %v are set", set, i)
+	}
+}
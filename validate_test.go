@@ -0,0 +1,113 @@
+package ines // nolint: testpackage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		b       []byte
+		want    []string
+		offsets []int
+	}{
+		{
+			name: "clean ines 1.0",
+			b:    rawRom([]byte{78, 69, 83, 26, 2, 1, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192),
+		},
+		{
+			name:    "ines 1.0 with trailing data and reserved bits",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 1, 0x01, 0, 0, 0x02, 0, 0, 0, 0, 0, 0}, 32768, 8192, 100),
+			want:    []string{CodeReservedBits, CodeTrailingData},
+			offsets: []int{9, 16 + 32768 + 8192},
+		},
+		{
+			name:    "diskdude",
+			b:       rawRom(diskDudeHeader, 32768, 8192),
+			want:    []string{CodeUnreliableBytes},
+			offsets: []int{7},
+		},
+		{
+			name:    "nrom with 48 KiB of prg-rom",
+			b:       rawRom([]byte{78, 69, 83, 26, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 49152, 8192),
+			want:    []string{CodePRGSize},
+			offsets: []int{4},
+		},
+		{
+			name:    "nrom with 16 KiB of chr-rom",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 16384),
+			want:    []string{CodeCHRSize},
+			offsets: []int{5},
+		},
+		{
+			name:    "unrom with chr-rom",
+			b:       rawRom([]byte{78, 69, 83, 26, 8, 1, 0x20, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 131072, 8192),
+			want:    []string{CodeCHRSize},
+			offsets: []int{5},
+		},
+		{
+			name:    "cprom with chr-rom",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 1, 0xD0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192),
+			want:    []string{CodeCHRSize},
+			offsets: []int{5},
+		},
+		{
+			name:    "ines 2.0 undefined submapper",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 1, 0x40, 0x08, 0x90, 0, 0, 0, 0, 0, 0, 0}, 32768, 8192),
+			want:    []string{CodeSubMapper},
+			offsets: []int{8},
+		},
+		{
+			name:    "ines 2.0 battery without prg-nvram",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 1, 0x12, 0x08, 0, 0, 0x07, 0, 0, 0, 0, 0}, 32768, 8192),
+			want:    []string{CodeBatteryWithoutNVRAM},
+			offsets: []int{10},
+		},
+		{
+			name:    "ines 2.0 without chr memory",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 0, 0x20, 0x08, 0, 0, 0, 0, 0, 0, 0, 0}, 32768),
+			want:    []string{CodeNoCHRMemory},
+			offsets: []int{11},
+		},
+		{
+			name:    "ines 2.0 regular nes with vs. system byte 13",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 1, 0, 0x08, 0, 0, 0, 0, 0, 0x21, 0, 0}, 32768, 8192),
+			want:    []string{CodeConsoleType},
+			offsets: []int{13},
+		},
+		{
+			name:    "ines 2.0 extended console type which fits in byte 7",
+			b:       rawRom([]byte{78, 69, 83, 26, 2, 1, 0, 0x0B, 0, 0, 0, 0, 0xFC, 0x01, 0, 0}, 32768, 8192),
+			want:    []string{CodeReservedBits, CodeConsoleType},
+			offsets: []int{12, 13},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			rom, err := Decode(tt2.b)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			var codes []string
+
+			var offsets []int
+
+			for _, diagnostic := range Validate(rom) {
+				codes = append(codes, diagnostic.Code)
+				offsets = append(offsets, diagnostic.Offset)
+			}
+
+			if !reflect.DeepEqual(codes, tt2.want) || !reflect.DeepEqual(offsets, tt2.offsets) {
+				t.Errorf("Validate() = %v at %v, want %v at %v", codes, offsets, tt2.want, tt2.offsets)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package ines
+
+// The 6502 reads the addresses of its interrupt handlers from the top of the CPU address space.
+const (
+	nmiVector   = 0xFFFA
+	resetVector = 0xFFFC
+	irqVector   = 0xFFFE
+	romStart    = 0x8000 // PRG-ROM is mapped at $8000-$FFFF
+	blankCheck  = 16     // bytes looked at for erased data
+)
+
+// Vector is an interrupt vector of the 6502.
+type Vector struct {
+	Address    uint16 // the CPU address the vector points to
+	Offset     int    // file offset of the vector itself
+	Target     int    // file offset of Address, or -1 if it's not in the bank fixed at power-on
+	OutsideROM bool   // Address is below $8000, where there is no PRG-ROM
+	Blank      bool   // the bytes at Target are all $FF, as erased or missing data is
+}
+
+// Vectors holds the interrupt vectors, as read from the PRG-ROM bank mapped at the top of the CPU address
+// space at power-on. Bank is its index in BankSize units.
+type Vectors struct {
+	Bank     int
+	BankSize int
+	NMI      Vector
+	Reset    Vector
+	IRQ      Vector
+}
+
+// fixedBank returns the size of the window mapped at the top of the CPU address space at power-on,
+// and the PRG-ROM offset of what is mapped there.
+// nolint: gomnd
+func fixedBank(mapper int, prgSize int) (int, int) {
+	window := 0x4000
+
+	switch mapper {
+	case 0, 3: // NROM, CNROM: 16 KiB mirrored, or 32 KiB
+		if prgSize >= 0x8000 {
+			window = 0x8000
+		}
+	case 7: // AxROM: 32 KiB switched, the power-on bank is unspecified and games repeat the vectors in each
+		window = 0x8000
+	case 1: // MMC1: the last 16 KiB at $C000, of the first 256 KiB on SUROM
+		if prgSize > 0x40000 {
+			return window, 0x40000 - window
+		}
+	case 4: // MMC3: the last 8 KiB at $E000
+		window = 0x2000
+	}
+
+	// UxROM, and most other mappers, fix the last 16 KiB at $C000
+	if window > prgSize {
+		window = prgSize
+	}
+
+	return window, prgSize - window
+}
+
+// ReadVectors reads the NMI, reset and IRQ vectors of rom, from the PRG-ROM bank its mapper fixes at the top
+// of the CPU address space at power-on. The mappers it knows about are NROM, UxROM, CNROM, AxROM, MMC1
+// and MMC3. The others are taken to fix the last 16 KiB at $C000.
+func ReadVectors(rom Rom) (Vectors, error) {
+	prgSize := len(rom.ProgramRom)
+	window, start := fixedBank(rom.Mapper, prgSize)
+
+	if window < 0x10000-nmiVector {
+		return Vectors{}, ErrSectionOutOfBounds{Section: "Vectors", Want: 0x10000 - nmiVector, Have: prgSize}
+	}
+
+	base := 0x10000 - window // CPU address of the start of the window
+	prgOffset := headerSize + len(rom.Trainer)
+
+	vector := func(at int) Vector {
+		v := Vector{Offset: prgOffset + start + at - base, Target: -1}
+		v.Address = uint16(rom.ProgramRom[start+at-base]) | uint16(rom.ProgramRom[start+at-base+1])<<8
+		v.OutsideROM = v.Address < romStart
+
+		// NROM-128 mirrors its 16 KiB at $8000 too
+		address := int(v.Address)
+		if address >= romStart && address < base && (rom.Mapper == 0 || rom.Mapper == 3) && window < 0x8000 {
+			address += window
+		}
+
+		if address >= base {
+			at := start + address - base
+			v.Target = prgOffset + at
+			v.Blank = true
+
+			for i := at; i < at+blankCheck && i < start+window; i++ {
+				v.Blank = v.Blank && rom.ProgramRom[i] == 0xFF
+			}
+		}
+
+		return v
+	}
+
+	return Vectors{
+		Bank:     start / window,
+		BankSize: window,
+		NMI:      vector(nmiVector),
+		Reset:    vector(resetVector),
+		IRQ:      vector(irqVector),
+	}, nil
+}
@@ -0,0 +1,146 @@
+package ines // nolint: testpackage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// prgWithVectors returns size bytes of $EA (NOP) whose last 6 bytes are the NMI, reset and IRQ vectors.
+func prgWithVectors(size int, nmi, reset, irq uint16) []byte {
+	prg := bytes.Repeat([]byte{0xEA}, size)
+	copy(prg[size-6:], []byte{byte(nmi), byte(nmi >> 8), byte(reset), byte(reset >> 8), byte(irq), byte(irq >> 8)})
+
+	return prg
+}
+
+func TestReadVectors(t *testing.T) {
+	t.Parallel()
+
+	blank := prgWithVectors(16384, 0xC000, 0xC010, 0xC020)
+	copy(blank, bytes.Repeat([]byte{0xFF}, 16))
+
+	surom := append(prgWithVectors(262144, 0xC000, 0xC000, 0xC000), make([]byte, 262144)...)
+
+	tests := []struct {
+		name     string
+		rom      Rom
+		bank     int
+		bankSize int
+		want     [3]Vector // NMI, reset, IRQ
+	}{
+		{
+			name:     "nrom-128 mirrored at $8000",
+			rom:      Rom{ProgramRom: prgWithVectors(16384, 0xC100, 0x8000, 0xFFF0)},
+			bankSize: 16384,
+			want: [3]Vector{
+				{Address: 0xC100, Offset: 16 + 0x3FFA, Target: 16 + 0x100},
+				{Address: 0x8000, Offset: 16 + 0x3FFC, Target: 16},
+				{Address: 0xFFF0, Offset: 16 + 0x3FFE, Target: 16 + 0x3FF0},
+			},
+		},
+		{
+			name:     "nrom-256 with trainer",
+			rom:      Rom{Trainer: make([]byte, 512), ProgramRom: prgWithVectors(32768, 0x8000, 0x8000, 0x8000)},
+			bankSize: 32768,
+			want: [3]Vector{
+				{Address: 0x8000, Offset: 528 + 0x7FFA, Target: 528},
+				{Address: 0x8000, Offset: 528 + 0x7FFC, Target: 528},
+				{Address: 0x8000, Offset: 528 + 0x7FFE, Target: 528},
+			},
+		},
+		{
+			name:     "uxrom switchable bank and ram",
+			rom:      Rom{Mapper: 2, ProgramRom: prgWithVectors(131072, 0x8000, 0xC000, 0x6000)},
+			bank:     7,
+			bankSize: 16384,
+			want: [3]Vector{
+				{Address: 0x8000, Offset: 16 + 0x1FFFA, Target: -1},
+				{Address: 0xC000, Offset: 16 + 0x1FFFC, Target: 16 + 0x1C000},
+				{Address: 0x6000, Offset: 16 + 0x1FFFE, Target: -1, OutsideROM: true},
+			},
+		},
+		{
+			name:     "mmc3 last 8 KiB",
+			rom:      Rom{Mapper: 4, ProgramRom: prgWithVectors(65536, 0xE000, 0xC000, 0xE123)},
+			bank:     7,
+			bankSize: 8192,
+			want: [3]Vector{
+				{Address: 0xE000, Offset: 16 + 0xFFFA, Target: 16 + 0xE000},
+				{Address: 0xC000, Offset: 16 + 0xFFFC, Target: -1},
+				{Address: 0xE123, Offset: 16 + 0xFFFE, Target: 16 + 0xE123},
+			},
+		},
+		{
+			name:     "mmc1 surom first 256 KiB",
+			rom:      Rom{Mapper: 1, ProgramRom: surom},
+			bank:     15,
+			bankSize: 16384,
+			want: [3]Vector{
+				{Address: 0xC000, Offset: 16 + 0x3FFFA, Target: 16 + 0x3C000},
+				{Address: 0xC000, Offset: 16 + 0x3FFFC, Target: 16 + 0x3C000},
+				{Address: 0xC000, Offset: 16 + 0x3FFFE, Target: 16 + 0x3C000},
+			},
+		},
+		{
+			name:     "axrom last 32 KiB",
+			rom:      Rom{Mapper: 7, ProgramRom: prgWithVectors(131072, 0x8000, 0x8000, 0x8000)},
+			bank:     3,
+			bankSize: 32768,
+			want: [3]Vector{
+				{Address: 0x8000, Offset: 16 + 0x1FFFA, Target: 16 + 0x18000},
+				{Address: 0x8000, Offset: 16 + 0x1FFFC, Target: 16 + 0x18000},
+				{Address: 0x8000, Offset: 16 + 0x1FFFE, Target: 16 + 0x18000},
+			},
+		},
+		{
+			name:     "erased data",
+			rom:      Rom{Mapper: 3, ProgramRom: blank},
+			bankSize: 16384,
+			want: [3]Vector{
+				{Address: 0xC000, Offset: 16 + 0x3FFA, Target: 16, Blank: true},
+				{Address: 0xC010, Offset: 16 + 0x3FFC, Target: 16 + 0x10},
+				{Address: 0xC020, Offset: 16 + 0x3FFE, Target: 16 + 0x20},
+			},
+		},
+		{
+			name:     "vectors pointing at themselves",
+			rom:      Rom{ProgramRom: prgWithVectors(16384, 0xFFFF, 0xFFFF, 0xFFFF)},
+			bankSize: 16384,
+			want: [3]Vector{
+				{Address: 0xFFFF, Offset: 16 + 0x3FFA, Target: 16 + 0x3FFF, Blank: true},
+				{Address: 0xFFFF, Offset: 16 + 0x3FFC, Target: 16 + 0x3FFF, Blank: true},
+				{Address: 0xFFFF, Offset: 16 + 0x3FFE, Target: 16 + 0x3FFF, Blank: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt2 := tt
+		t.Run(tt2.name, func(t *testing.T) {
+			t.Parallel()
+
+			vectors, err := ReadVectors(tt2.rom)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if vectors.Bank != tt2.bank || vectors.BankSize != tt2.bankSize {
+				t.Errorf("ReadVectors() bank = %v of %v bytes, want %v of %v", vectors.Bank, vectors.BankSize, tt2.bank, tt2.bankSize)
+			}
+
+			if got := [3]Vector{vectors.NMI, vectors.Reset, vectors.IRQ}; got != tt2.want {
+				t.Errorf("ReadVectors() = %+v, want %+v", got, tt2.want)
+			}
+		})
+	}
+}
+
+func TestReadVectors_noPRG(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReadVectors(Rom{ProgramRom: make([]byte, 4)})
+	if !errors.Is(err, ErrSectionOutOfBounds{Section: "Vectors", Want: 6, Have: 4}) {
+		t.Errorf("ReadVectors() error = %v, want ErrSectionOutOfBounds", err)
+	}
+}